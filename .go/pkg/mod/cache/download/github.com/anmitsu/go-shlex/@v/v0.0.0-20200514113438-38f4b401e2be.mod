@@ -0,0 +1,3 @@
+module github.com/anmitsu/go-shlex
+
+go 1.13
@@ -0,0 +1,25 @@
+module github.com/charmbracelet/bubbletea
+
+go 1.18
+
+require (
+	github.com/charmbracelet/lipgloss v1.0.0
+	github.com/charmbracelet/x/ansi v0.4.5
+	github.com/charmbracelet/x/term v0.2.1
+	github.com/erikgeiser/coninput v0.0.0-20211004153227-1c3628e74d0f
+	github.com/mattn/go-localereader v0.0.1
+	github.com/muesli/ansi v0.0.0-20230316100256-276c6243b2f6
+	github.com/muesli/cancelreader v0.2.2
+	golang.org/x/sync v0.9.0
+	golang.org/x/sys v0.27.0
+)
+
+require (
+	github.com/aymanbagabas/go-osc52/v2 v2.0.1 // indirect
+	github.com/lucasb-eyer/go-colorful v1.2.0 // indirect
+	github.com/mattn/go-isatty v0.0.20 // indirect
+	github.com/mattn/go-runewidth v0.0.15 // indirect
+	github.com/muesli/termenv v0.15.2 // indirect
+	github.com/rivo/uniseg v0.4.7 // indirect
+	golang.org/x/text v0.3.8 // indirect
+)
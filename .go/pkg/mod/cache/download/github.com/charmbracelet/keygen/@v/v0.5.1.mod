@@ -0,0 +1,7 @@
+module github.com/charmbracelet/keygen
+
+go 1.17
+
+require golang.org/x/crypto v0.26.0
+
+require golang.org/x/sys v0.23.0 // indirect
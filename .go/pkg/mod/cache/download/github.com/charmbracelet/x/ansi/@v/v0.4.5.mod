@@ -0,0 +1,5 @@
+module github.com/charmbracelet/x/ansi
+
+go 1.18
+
+require github.com/rivo/uniseg v0.4.7
@@ -0,0 +1,5 @@
+module github.com/charmbracelet/x/exp/golden
+
+go 1.19
+
+require github.com/aymanbagabas/go-udiff v0.2.0
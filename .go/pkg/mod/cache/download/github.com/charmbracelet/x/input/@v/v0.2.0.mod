@@ -0,0 +1,16 @@
+module github.com/charmbracelet/x/input
+
+go 1.18
+
+require (
+	github.com/charmbracelet/x/ansi v0.2.2
+	github.com/erikgeiser/coninput v0.0.0-20211004153227-1c3628e74d0f
+	github.com/muesli/cancelreader v0.2.2
+	github.com/xo/terminfo v0.0.0-20220910002029-abceb7e1c41e
+	golang.org/x/sys v0.24.0
+)
+
+require (
+	github.com/rivo/uniseg v0.4.7 // indirect
+	golang.org/x/exp v0.0.0-20231006140011-7918f672742d // indirect
+)
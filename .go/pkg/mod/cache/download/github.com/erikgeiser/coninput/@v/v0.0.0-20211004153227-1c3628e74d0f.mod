@@ -0,0 +1,5 @@
+module github.com/erikgeiser/coninput
+
+go 1.16
+
+require golang.org/x/sys v0.0.0-20210809222454-d867a43fc93e
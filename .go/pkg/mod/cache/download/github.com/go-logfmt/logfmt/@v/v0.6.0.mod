@@ -0,0 +1,3 @@
+module github.com/go-logfmt/logfmt
+
+go 1.17
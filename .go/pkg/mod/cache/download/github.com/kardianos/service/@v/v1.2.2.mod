@@ -0,0 +1,5 @@
+module github.com/kardianos/service
+
+go 1.12
+
+require golang.org/x/sys v0.0.0-20201015000850-e3ed0017c211
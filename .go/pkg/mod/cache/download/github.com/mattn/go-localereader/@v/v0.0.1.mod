@@ -0,0 +1 @@
+module github.com/mattn/go-localereader
@@ -0,0 +1 @@
+module github.com/sahilm/fuzzy
@@ -0,0 +1,11 @@
+module golang.org/x/crypto
+
+go 1.20
+
+require (
+	golang.org/x/net v0.21.0 // tagx:ignore
+	golang.org/x/sys v0.23.0
+	golang.org/x/term v0.23.0
+)
+
+require golang.org/x/text v0.17.0 // indirect
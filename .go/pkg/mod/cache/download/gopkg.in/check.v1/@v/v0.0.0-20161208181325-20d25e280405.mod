@@ -0,0 +1 @@
+module gopkg.in/check.v1
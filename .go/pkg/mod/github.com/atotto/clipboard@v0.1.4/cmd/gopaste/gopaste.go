@@ -0,0 +1,16 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/atotto/clipboard"
+)
+
+func main() {
+	text, err := clipboard.ReadAll()
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Print(text)
+}
@@ -0,0 +1 @@
+module github.com/atotto/clipboard
@@ -3,21 +3,31 @@ package main
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net"
 	"os"
+	"os/exec"
 	"os/signal"
+	"path/filepath"
+	"slices"
+	"strings"
 	"syscall"
 	"time"
 
-	// "walross/nixtea/internal/bubbler"
+	"walross/nixtea/internal/bubbler"
 	"walross/nixtea/internal/cli"
 	"walross/nixtea/internal/config"
+	"walross/nixtea/internal/db"
+	"walross/nixtea/internal/nixapi"
+	"walross/nixtea/internal/supervisor"
 	"walross/nixtea/internal/suprvisor"
 
+	"github.com/charmbracelet/keygen"
 	"github.com/charmbracelet/log"
 	"github.com/charmbracelet/ssh"
 	"github.com/charmbracelet/wish"
 	"github.com/charmbracelet/wish/logging"
+	gossh "golang.org/x/crypto/ssh"
 )
 
 const (
@@ -25,22 +35,183 @@ const (
 	port = "23234"
 )
 
+// setupLogging applies the configured log level, format and destination
+// to the default charmbracelet/log logger
+func setupLogging(cfg *config.Config) {
+	level, err := log.ParseLevel(cfg.LogLevel)
+	if err != nil {
+		log.Warn("Invalid LOG_LEVEL, using info", "value", cfg.LogLevel)
+		level = log.InfoLevel
+	}
+	log.SetLevel(level)
+
+	switch cfg.LogFormat {
+	case "json":
+		log.SetFormatter(log.JSONFormatter)
+	case "text", "":
+		log.SetFormatter(log.TextFormatter)
+	default:
+		log.Warn("Invalid LOG_FORMAT, using text", "value", cfg.LogFormat)
+	}
+
+	if cfg.LogOutput != "" && cfg.LogOutput != "stderr" {
+		f, err := os.OpenFile(cfg.LogOutput, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			log.Error("Failed to open log output, using stderr", "path", cfg.LogOutput, "error", err)
+		} else {
+			log.SetOutput(f)
+		}
+	}
+}
+
+// runConfigCheck validates the loaded configuration without starting the
+// SSH server: directories writable, host key usable, DB opens, nix
+// available. Prints a ✓/✗ report and exits 0 when everything passes,
+// 1 otherwise — made for pre-deploy validation in CI.
+func runConfigCheck(cfg *config.Config) {
+	type check struct {
+		name   string
+		ok     bool
+		detail string
+	}
+	var checks []check
+
+	// Data and DB directories writable
+	for _, dir := range []struct{ name, path string }{
+		{"data dir writable", cfg.DataDir},
+		{"db dir writable", cfg.DBDir},
+	} {
+		if err := os.MkdirAll(dir.path, 0755); err != nil {
+			checks = append(checks, check{dir.name, false, fmt.Sprintf("%s: %v", dir.path, err)})
+			continue
+		}
+		probe := filepath.Join(dir.path, ".nixtea-config-check")
+		if err := os.WriteFile(probe, []byte("ok"), 0644); err != nil {
+			checks = append(checks, check{dir.name, false, fmt.Sprintf("%s: %v", dir.path, err)})
+		} else {
+			os.Remove(probe)
+			checks = append(checks, check{dir.name, true, dir.path})
+		}
+	}
+
+	// Host key parseable. A missing key is fine — it's generated on
+	// first startup — but an unreadable or corrupt one is not.
+	if data, err := os.ReadFile(cfg.HostKeyPath); err != nil {
+		if os.IsNotExist(err) {
+			checks = append(checks, check{"host key", true, cfg.HostKeyPath + " (will be generated on first start)"})
+		} else {
+			checks = append(checks, check{"host key", false, fmt.Sprintf("%s: %v", cfg.HostKeyPath, err)})
+		}
+	} else if _, err := gossh.ParsePrivateKey(data); err != nil {
+		checks = append(checks, check{"host key", false, fmt.Sprintf("%s: %v", cfg.HostKeyPath, err)})
+	} else {
+		checks = append(checks, check{"host key", true, cfg.HostKeyPath})
+	}
+
+	// Database opens
+	if database, err := db.NewStore(cfg); err != nil {
+		checks = append(checks, check{"database opens", false, err.Error()})
+	} else {
+		database.Close()
+		checks = append(checks, check{"database opens", true, filepath.Join(cfg.DBDir, cfg.DBName)})
+	}
+
+	// nix present + version
+	if out, err := exec.Command("nix", "--version").Output(); err != nil {
+		checks = append(checks, check{"nix available", false, "nix not found in PATH"})
+	} else {
+		checks = append(checks, check{"nix available", true, strings.TrimSpace(string(out))})
+	}
+
+	failed := 0
+	for _, c := range checks {
+		mark := "✓"
+		if !c.ok {
+			mark = "✗"
+			failed++
+		}
+		fmt.Printf("%s %-18s %s\n", mark, c.name, c.detail)
+	}
+
+	if failed > 0 {
+		fmt.Printf("\n%d of %d checks failed\n", failed, len(checks))
+		os.Exit(1)
+	}
+	fmt.Printf("\nAll %d checks passed\n", len(checks))
+	os.Exit(0)
+}
+
 func main() {
 	cfg, err := config.NewCfg()
 	if err != nil {
 		log.Error("Failed to initialize configuration", "error", err)
 		os.Exit(1)
 	}
+	setupLogging(cfg)
+
+	// Validate-and-exit mode: report on the configuration without
+	// starting the SSH server
+	if len(os.Args) > 1 && os.Args[1] == "--config-check" {
+		runConfigCheck(cfg)
+	}
+	nixapi.SetDefaultTimeouts(cfg.EvalTimeout, cfg.BuildTimeout, cfg.UpdateTimeout)
+	nixapi.SetBinaryCaches(cfg.Substituters, cfg.TrustedPublicKeys)
+	nixapi.SetBuildLimits(cfg.MaxBuildJobs, cfg.BuildCores)
+
+	// Generate a host key on first run so the server works out of the box
+	if _, err := os.Stat(cfg.HostKeyPath); os.IsNotExist(err) {
+		if _, err := keygen.New(cfg.HostKeyPath, keygen.WithKeyType(keygen.Ed25519), keygen.WithWrite()); err != nil {
+			log.Error("Failed to generate SSH host key", "path", cfg.HostKeyPath, "error", err)
+			os.Exit(1)
+		}
+		log.Info("Generated new SSH host key", "path", cfg.HostKeyPath)
+	}
+
 	sp := suprvisor.NewSupervisor()
+	// Pin built outputs against nix garbage collection while tracked
+	sp.SetGCRootsDir(filepath.Join(cfg.DataDir, "gcroots"))
+	// Catch processes that die behind the supervisor's back
+	sp.StartReconciler(cfg.ReconcileInterval)
+
+	// The TUI dashboard runs on its own supervisor (process handles and
+	// broadcasts for interactive sessions); the CLI path keeps using sp
+	sv := supervisor.NewSupervisor()
+
+	// Probe the listen address up front so a taken port is an obvious
+	// diagnosis instead of an opaque ListenAndServe failure later
+	addr := net.JoinHostPort(cfg.Host, cfg.Port)
+	if ln, err := net.Listen("tcp", addr); err != nil {
+		log.Error("Listen address unavailable — is nixtea already running?", "addr", addr, "error", err)
+		os.Exit(1)
+	} else {
+		ln.Close()
+	}
 
 	s, err := wish.NewServer(
-		wish.WithAddress(net.JoinHostPort(cfg.Host, cfg.Port)),
+		wish.WithAddress(addr),
 		wish.WithHostKeyPath(cfg.HostKeyPath),
 		wish.WithMiddleware(
 			cli.CreateMiddleware(cfg, sp),
-			// bubbler.BubblerMiddleware(sp, cfg),
+			// The TUI wraps the CLI: command sessions fall through to it,
+			// interactive ones get the dashboard
+			bubbler.BubblerMiddleware(sv, cfg),
+			cli.SessionLimitMiddleware(cfg),
+			cli.AllowlistMiddleware(cfg),
 			logging.Middleware(),
 		),
+		// Allow client-initiated direct-tcpip forwards (ssh -L) so users
+		// can reach supervised services through the nixtea connection
+		func(s *ssh.Server) error {
+			s.LocalPortForwardingCallback = func(ctx ssh.Context, host string, port uint32) bool {
+				log.Info("Local port forward requested", "host", host, "port", port)
+				return true
+			}
+			s.ChannelHandlers = map[string]ssh.ChannelHandler{
+				"session":      ssh.DefaultSessionHandler,
+				"direct-tcpip": ssh.DirectTCPIPHandler,
+			}
+			return nil
+		},
 	)
 	if err != nil {
 		log.Error("Could not start server", "error", err)
@@ -51,6 +222,83 @@ func main() {
 	signal.Notify(done, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
 	log.Info("Starting SSH server", "host", host, "port", port)
 
+	// Reload hot-reloadable config on SIGHUP without dropping
+	// connections. The listen address and host key path require a full
+	// restart and are deliberately left untouched.
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	go func() {
+		for range hup {
+			fresh, err := config.NewCfg()
+			if err != nil {
+				log.Error("SIGHUP: failed to reload configuration", "error", err)
+				continue
+			}
+
+			// Writes go through cfg.Reload so middlewares and commands
+			// reading these fields mid-session see a consistent value
+			cfg.Reload.Lock()
+			if fresh.MaxSessions != cfg.MaxSessions {
+				log.Info("SIGHUP: max sessions changed", "old", cfg.MaxSessions, "new", fresh.MaxSessions)
+				cfg.MaxSessions = fresh.MaxSessions
+			}
+			if fresh.GroupPackages != cfg.GroupPackages {
+				log.Info("SIGHUP: package grouping changed", "old", cfg.GroupPackages, "new", fresh.GroupPackages)
+				cfg.GroupPackages = fresh.GroupPackages
+			}
+			if fresh.EvalTimeout != cfg.EvalTimeout || fresh.BuildTimeout != cfg.BuildTimeout || fresh.UpdateTimeout != cfg.UpdateTimeout {
+				log.Info("SIGHUP: nix timeouts changed",
+					"eval", fresh.EvalTimeout,
+					"build", fresh.BuildTimeout,
+					"update", fresh.UpdateTimeout)
+				cfg.EvalTimeout = fresh.EvalTimeout
+				cfg.BuildTimeout = fresh.BuildTimeout
+				cfg.UpdateTimeout = fresh.UpdateTimeout
+				nixapi.SetDefaultTimeouts(cfg.EvalTimeout, cfg.BuildTimeout, cfg.UpdateTimeout)
+			}
+			if !slices.Equal(fresh.Substituters, cfg.Substituters) || !slices.Equal(fresh.TrustedPublicKeys, cfg.TrustedPublicKeys) {
+				log.Info("SIGHUP: binary caches changed",
+					"substituters", strings.Join(fresh.Substituters, " "),
+					"trustedKeys", len(fresh.TrustedPublicKeys))
+				cfg.Substituters = fresh.Substituters
+				cfg.TrustedPublicKeys = fresh.TrustedPublicKeys
+				nixapi.SetBinaryCaches(cfg.Substituters, cfg.TrustedPublicKeys)
+			}
+			if fresh.MaxBuildJobs != cfg.MaxBuildJobs || fresh.BuildCores != cfg.BuildCores {
+				log.Info("SIGHUP: build limits changed",
+					"maxJobs", fresh.MaxBuildJobs,
+					"cores", fresh.BuildCores)
+				cfg.MaxBuildJobs = fresh.MaxBuildJobs
+				cfg.BuildCores = fresh.BuildCores
+				nixapi.SetBuildLimits(cfg.MaxBuildJobs, cfg.BuildCores)
+			}
+			if !slices.Equal(fresh.AllowedCIDRs, cfg.AllowedCIDRs) {
+				log.Info("SIGHUP: connection allowlist changed",
+					"cidrs", strings.Join(fresh.AllowedCIDRs, " "))
+				// AllowlistMiddleware re-reads this on every connection
+				cfg.AllowedCIDRs = fresh.AllowedCIDRs
+			}
+			cfg.Reload.Unlock()
+
+			if fresh.LogLevel != cfg.LogLevel || fresh.LogFormat != cfg.LogFormat || fresh.LogOutput != cfg.LogOutput {
+				log.Info("SIGHUP: logging configuration changed",
+					"level", fresh.LogLevel,
+					"format", fresh.LogFormat,
+					"output", fresh.LogOutput)
+				cfg.LogLevel = fresh.LogLevel
+				cfg.LogFormat = fresh.LogFormat
+				cfg.LogOutput = fresh.LogOutput
+				setupLogging(cfg)
+			}
+			if fresh.Host != cfg.Host || fresh.Port != cfg.Port {
+				log.Warn("SIGHUP: listen address changed; restart required to apply",
+					"host", fresh.Host, "port", fresh.Port)
+			}
+
+			log.Info("SIGHUP: configuration reloaded")
+		}
+	}()
+
 	go func() {
 		if err = s.ListenAndServe(); err != nil && !errors.Is(err, ssh.ErrServerClosed) {
 			log.Error("Could not start server", "error", err)
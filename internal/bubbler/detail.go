@@ -1,6 +1,7 @@
 package bubbler
 
 import (
+	"encoding/base64"
 	"fmt"
 	"strings"
 	"time"
@@ -44,6 +45,47 @@ type DetailState struct {
 	logsViewport viewport.Model
 	outputLines  []LogLine
 	Pid          int
+	startedAt    time.Time
+
+	// When set, only error-ish lines (stderr output and explicit error
+	// messages) are shown in the viewport
+	errorsOnly bool
+
+	// Pending OSC52 clipboard escape to emit on the next render, plus a
+	// footer confirmation and when the copy happened (the notice fades
+	// after copyNoticeTimeout)
+	copySeq    string
+	copyNotice string
+	copiedAt   time.Time
+}
+
+// copyNoticeTimeout is how long the "copied" footer confirmation stays
+// up before fading
+const copyNoticeTimeout = 3 * time.Second
+
+// osc52Copy builds the OSC52 escape sequence that asks the terminal to
+// place text on the system clipboard; works over SSH with supporting
+// terminals
+func osc52Copy(text string) string {
+	return "\x1b]52;c;" + base64.StdEncoding.EncodeToString([]byte(text)) + "\x07"
+}
+
+// formatTimestamp renders a time with the configured format, falling
+// back to local short time when no config is attached
+func (m model) formatTimestamp(t time.Time) string {
+	if m.cfg != nil {
+		return m.cfg.FormatTimestamp(t)
+	}
+	return t.Local().Format("15:04:05")
+}
+
+// errorLineStyle colors stderr/error lines so they stand out in the stream
+var errorLineStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("203"))
+
+// isErrorLine reports whether a log line should survive the errors-only
+// filter: stderr output or anything that looks like an error message
+func isErrorLine(text string) bool {
+	return strings.HasPrefix(text, "[stderr]") || strings.Contains(strings.ToLower(text), "error")
 }
 
 func (m model) updateDetail(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -51,6 +93,9 @@ func (m model) updateDetail(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "r":
+			if m.readOnly() {
+				return m, nil
+			}
 			log.Info("Running process", "pkg", m.detailState.pkg.Name)
 			if err := m.sv.StartService(m.detailState.pkg.Name, m.detailState.pkg.Key, m.inputState.urlInput); err != nil {
 				m.detailState.outputLines = append(m.detailState.outputLines, LogLine{
@@ -58,6 +103,7 @@ func (m model) updateDetail(msg tea.Msg) (tea.Model, tea.Cmd) {
 					Timestamp: time.Now(),
 				})
 			} else {
+				m.detailState.startedAt = time.Now()
 				m.detailState.outputLines = append(m.detailState.outputLines, LogLine{
 					Text:      "Service started successfully",
 					Timestamp: time.Now(),
@@ -67,6 +113,9 @@ func (m model) updateDetail(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 
 		case "s":
+			if m.readOnly() {
+				return m, nil
+			}
 			if err := m.sv.StopService(m.detailState.pkg.Key); err != nil {
 				m.detailState.outputLines = append(m.detailState.outputLines, LogLine{
 					Text:      fmt.Sprintf("Error stopping service: %v", err),
@@ -81,6 +130,19 @@ func (m model) updateDetail(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.updateLogsViewport()
 			return m, nil
 
+		case "e":
+			m.detailState.errorsOnly = !m.detailState.errorsOnly
+			m.updateLogsViewport()
+			return m, nil
+
+		case "c":
+			// Copy the exact run command for this package
+			runCmd := fmt.Sprintf("nix run %s#%s", m.inputState.urlInput, m.detailState.pkg.Key)
+			m.detailState.copySeq = osc52Copy(runCmd)
+			m.detailState.copyNotice = "copied run command"
+			m.detailState.copiedAt = time.Now()
+			return m, nil
+
 		case "esc":
 			m.currentPane = PaneList
 			return m, nil
@@ -94,9 +156,17 @@ func (m model) updateDetail(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 // Helper function to update the viewport content
 func (m *model) updateLogsViewport() {
-	textLines := make([]string, len(m.detailState.outputLines))
-	for i, line := range m.detailState.outputLines {
-		textLines[i] = line.Text
+	textLines := make([]string, 0, len(m.detailState.outputLines))
+	for _, line := range m.detailState.outputLines {
+		isError := isErrorLine(line.Text)
+		if m.detailState.errorsOnly && !isError {
+			continue
+		}
+		if isError {
+			textLines = append(textLines, errorLineStyle.Render(line.Text))
+		} else {
+			textLines = append(textLines, line.Text)
+		}
 	}
 	m.detailState.logsViewport.SetContent(strings.Join(textLines, "\n"))
 	m.detailState.logsViewport.GotoBottom()
@@ -117,12 +187,54 @@ func (m model) updateDetailViewport(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+// Layout bounds for the detail pane. Below these the side-by-side
+// two-column layout stops making sense.
+const (
+	stackedLayoutWidth = 80 // stack columns vertically under this width
+	minViewportWidth   = 20
+	minViewportHeight  = 3
+	tinyTerminalWidth  = 24
+	tinyTerminalHeight = 8
+)
+
+// detailLayout computes clamped panel dimensions for the current
+// terminal size and reports whether the columns should stack vertically
+func (m model) detailLayout() (logsWidth, metricsWidth, contentHeight int, stacked bool) {
+	stacked = m.width < stackedLayoutWidth
+
+	if stacked {
+		// Full-width panels, sharing the vertical space
+		logsWidth = m.width - 2
+		metricsWidth = m.width - 2
+		contentHeight = (m.height - 10) / 2
+	} else {
+		logsWidth = (m.width * 2) / 3
+		metricsWidth = m.width - logsWidth - 3
+		contentHeight = m.height - 10
+	}
+
+	// Never hand lipgloss a negative or useless dimension
+	if logsWidth < minViewportWidth+4 {
+		logsWidth = minViewportWidth + 4
+	}
+	if metricsWidth < minViewportWidth {
+		metricsWidth = minViewportWidth
+	}
+	if contentHeight < minViewportHeight+3 {
+		contentHeight = minViewportHeight + 3
+	}
+	return
+}
+
 func (m model) viewDetail() string {
+	// The degenerate case: nothing useful fits, say so instead of
+	// rendering overlapping garbage
+	if (m.width > 0 && m.width < tinyTerminalWidth) || (m.height > 0 && m.height < tinyTerminalHeight) {
+		return "terminal too small"
+	}
+
 	// Calculate dimensions
-	totalWidth := m.width
-	logsWidth := (totalWidth * 2) / 3
-	metricsWidth := totalWidth - logsWidth - 3
-	contentHeight := m.height - 10
+	logsWidth, metricsWidth, contentHeight, stacked := m.detailLayout()
 
 	// Initialize viewport if needed
 	if m.detailState.logsViewport.Width == 0 {
@@ -131,7 +243,11 @@ func (m model) viewDetail() string {
 	}
 
 	// Create logs section
-	logsTitle := titleStyle.Width(logsWidth - 4).Render("Logs")
+	logsLabel := "Logs"
+	if m.detailState.errorsOnly {
+		logsLabel = "Logs (errors only)"
+	}
+	logsTitle := titleStyle.Width(logsWidth - 4).Render(logsLabel)
 	logsContent := m.detailState.logsViewport.View()
 	logsColumn := logsContainerStyle.Width(logsWidth).Render(
 		lipgloss.JoinVertical(lipgloss.Left,
@@ -174,7 +290,7 @@ func (m model) viewDetail() string {
 		if metadata.IsRunning {
 			metrics = append(metrics,
 				fmt.Sprintf("PID: %d", metadata.PID),
-				fmt.Sprintf("Start Time: %s", metadata.StartTime.Format("15:04:05")),
+				fmt.Sprintf("Start Time: %s", m.formatTimestamp(metadata.StartTime)),
 				fmt.Sprintf("Uptime: %s", metadata.Uptime),
 				fmt.Sprintf("Memory Usage: %s", metadata.MemoryUsage),
 				fmt.Sprintf("CPU Usage: %s", metadata.CPUUsage),
@@ -193,16 +309,28 @@ func (m model) viewDetail() string {
 		),
 	)
 
-	// Combine columns
-	columns := lipgloss.JoinHorizontal(lipgloss.Top, logsColumn, metricsColumn)
+	// Combine columns: side by side normally, stacked on narrow terminals
+	var columns string
+	if stacked {
+		columns = lipgloss.JoinVertical(lipgloss.Left, logsColumn, metricsColumn)
+	} else {
+		columns = lipgloss.JoinHorizontal(lipgloss.Top, logsColumn, metricsColumn)
+	}
+
+	help := "↑/k,↓/j: scroll • pgup/pgdown: page scroll • r: run • s: stop • e: errors only • c: copy • esc: back • q: quit"
+	if m.detailState.copyNotice != "" {
+		help = m.detailState.copyNotice + " • " + help
+	}
 
-	// Build final view
-	return lipgloss.JoinVertical(
+	// Any pending OSC52 escape rides along with the render; terminals
+	// strip it from display and update the clipboard
+	view := lipgloss.JoinVertical(
 		lipgloss.Left,
 		m.viewHeader(),
 		"",
 		columns,
 		"",
-		m.viewFooter("↑/k,↓/j: scroll • pgup/pgdown: page scroll • r: run • s: stop • esc: back • q: quit"),
+		m.viewFooter(help),
 	)
+	return m.detailState.copySeq + view
 }
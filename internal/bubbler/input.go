@@ -19,19 +19,21 @@ func (m model) updateInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "enter":
 		if m.inputState.focused {
 
-			_, erro := m.db.GetRepos()
-			if erro != nil {
-				m.inputState.errorMsg = fmt.Sprintf("Failed to get repos: %v", erro)
-				return m, nil
-			}
-
 			packages, err := m.nixClient.GetFormattedPackages(m.inputState.urlInput)
 			if err != nil {
 				m.inputState.errorMsg = fmt.Sprintf("Failed to get packages: %v", err)
 				return m, nil
 			}
 
+			// Persist through the same path the CLI uses; re-adding an
+			// existing URL just re-activates it
+			if _, err := m.db.SaveRepo(m.inputState.urlInput); err != nil {
+				m.inputState.errorMsg = fmt.Sprintf("Failed to save repo: %v", err)
+				return m, nil
+			}
+
 			m.listState.packages = packages
+			m.listState.labels = packageLabels(m.db)
 			m.currentPane = PaneList
 		}
 	case "backspace":
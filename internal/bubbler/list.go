@@ -1,8 +1,11 @@
 package bubbler
 
 import (
+	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
+	"walross/nixtea/internal/db"
 	"walross/nixtea/internal/nixapi"
 	"walross/nixtea/internal/supervisor"
 
@@ -14,21 +17,50 @@ import (
 type ListState struct {
 	packages      []nixapi.PackageDisplay
 	selectedIndex int
+	statuses      map[string]string
+
+	// Friendly display labels from 'pkgs rename', keyed by package key
+	labels map[string]string
 }
 
 func (m model) updateList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
-	case "up":
+	case "up", "k":
 		if m.listState.selectedIndex > 0 {
 			m.listState.selectedIndex--
 		}
-	case "down":
+	case "down", "j":
 		if m.listState.selectedIndex < len(m.listState.packages)-1 {
 			m.listState.selectedIndex++
 		}
+	case "g":
+		m.listState.selectedIndex = 0
+	case "G":
+		if len(m.listState.packages) > 0 {
+			m.listState.selectedIndex = len(m.listState.packages) - 1
+		}
+
+	case "R":
+		// Cheap refresh: re-read the package list without touching the
+		// flake lock
+		go func() {
+			packages, err := m.nixClient.GetFormattedPackages(m.inputState.urlInput)
+			if err != nil {
+				m.sv.Broadcast(supervisor.NewLogLineMsg{
+					Text:      fmt.Sprintf("Error getting packages: %v", err),
+					Timestamp: time.Now(),
+				})
+				return
+			}
+			m.sv.Broadcast(UpdateListSuccessMsg{packages: packages})
+		}()
+		return m, nil
 
 	case "u", "U":
-		// Start update in a goroutine to avoid blocking UI
+		if m.cfg != nil && m.cfg.ReadOnly {
+			return m, nil
+		}
+		// The heavyweight path: updates the flake lock, then reloads
 		go func() {
 			if err := m.nixClient.UpdateFlake(m.inputState.urlInput); err != nil {
 				// Just broadcast the error as a log message
@@ -64,6 +96,17 @@ func (m model) updateList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			pkg := m.listState.packages[m.listState.selectedIndex]
 			m.detailState.pkg = pkg
 
+			// Backfill from the retained buffer so processes started
+			// elsewhere (CLI, an earlier session) don't show a blank pane
+			m.detailState.outputLines = nil
+			for _, text := range m.sv.RetainedOutput(pkg.Key) {
+				m.detailState.outputLines = append(m.detailState.outputLines, LogLine{
+					Text:      text,
+					Timestamp: time.Now(),
+				})
+			}
+			m.updateLogsViewport()
+
 			// Initialize viewport
 			vp := viewport.New(m.width, m.height-6)
 			vp.SetContent("")
@@ -76,15 +119,98 @@ func (m model) updateList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// packageLabels loads the friendly display labels saved for the active
+// repository's packages. Any failure just means no labels — the list
+// falls back to flake names.
+func packageLabels(database db.Store) map[string]string {
+	repos, err := database.GetRepos()
+	if err != nil {
+		return nil
+	}
+	var repoID string
+	for _, repo := range repos {
+		if repo.Active {
+			repoID = repo.ID
+			break
+		}
+	}
+	if repoID == "" {
+		return nil
+	}
+
+	configs, err := database.GetPackageConfigs(repoID)
+	if err != nil {
+		return nil
+	}
+
+	labels := make(map[string]string)
+	for key, raw := range configs {
+		var cfg struct {
+			Label string `json:"label"`
+		}
+		if err := json.Unmarshal([]byte(raw), &cfg); err == nil && cfg.Label != "" {
+			labels[key] = cfg.Label
+		}
+	}
+	return labels
+}
+
+// listGroup derives a display group for a package key: the prefix
+// before the first dash, or "other" for keys without one
+func listGroup(key string) string {
+	if idx := strings.Index(key, "-"); idx > 0 {
+		return key[:idx]
+	}
+	return "other"
+}
+
 func (m model) viewList() string {
 	var s string
 	s += m.viewHeader()
 	s += "\n" // Add space after header
 
+	grouping := m.cfg != nil && m.cfg.GroupPackages
+	lastGroup := ""
+
 	for i, pkg := range m.listState.packages {
-		line := fmt.Sprintf("• %s %s",
-			pkg.Name,
-			termenv.String("#"+pkg.Key).Foreground(termenv.ANSIBrightBlack))
+		// Print a group header whenever the derived group changes
+		if grouping {
+			if group := listGroup(pkg.Key); group != lastGroup {
+				s += termenv.String(group).Foreground(termenv.ANSIBrightMagenta).String() + "\n"
+				lastGroup = group
+			}
+		}
+		name := pkg.Name
+		if label, ok := m.listState.labels[pkg.Key]; ok && label != "" {
+			name = label
+		}
+
+		// Honor KEY_DISPLAY: name, key, or (the default) both
+		keyDisplay := "both"
+		if m.cfg != nil && m.cfg.KeyDisplay != "" {
+			keyDisplay = m.cfg.KeyDisplay
+		}
+		var line string
+		switch keyDisplay {
+		case "name":
+			line = fmt.Sprintf("• %s", name)
+		case "key":
+			line = fmt.Sprintf("• %s",
+				termenv.String("#"+pkg.Key).Foreground(termenv.ANSIBrightBlack))
+		default:
+			line = fmt.Sprintf("• %s %s",
+				name,
+				termenv.String("#"+pkg.Key).Foreground(termenv.ANSIBrightBlack))
+		}
+
+		// Show a status badge driven by ProcessStateChangedMsg broadcasts
+		if status, ok := m.listState.statuses[pkg.Key]; ok {
+			badge := termenv.String(status).Foreground(termenv.ANSIBrightBlack)
+			if status == "running" {
+				badge = termenv.String(status).Foreground(termenv.ANSIGreen)
+			}
+			line += " " + badge.String()
+		}
 
 		if i == m.listState.selectedIndex {
 			s += termenv.String(line).Foreground(termenv.ANSIGreen).String() + "\n"
@@ -94,6 +220,6 @@ func (m model) viewList() string {
 	}
 
 	s += "\n" // Add space before footer
-	s += m.viewFooter("↑/↓: navigate • enter: select • U: update • esc: back • q: quit")
+	s += m.viewFooter("↑/k,↓/j: navigate • g/G: top/bottom • enter: select • R: refresh list • U: update flake • esc: back • q: quit")
 	return s
 }
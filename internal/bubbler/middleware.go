@@ -47,22 +47,31 @@ type model struct {
 	height      int
 	term        string
 	time        time.Time
-	db          *db.DB
+	db          db.Store
 	nixClient   *nixapi.Client
 	program     *tea.Program
 	sess        ssh.Session
 	sv          *supervisor.Supervisor
 	cfg         *config.Config
+
+	// SSH public key fingerprint of the connected user, used to restore
+	// their place after a transient disconnect
+	fingerprint string
 }
 
 func BubblerMiddleware(sv *supervisor.Supervisor, cfg *config.Config) wish.Middleware {
-	db, err := db.New(cfg)
+	db, err := db.NewStore(cfg)
 	if err != nil {
 		log.Error("Failed to initialize database", "error", err)
 		return nil
 	}
 
-	nixClient := nixapi.NewClient()
+	nixClient := nixapi.NewClient(nixapi.CurrentSystem())
+
+	// Packages configured to run under a pseudo-terminal
+	for _, key := range cfg.TTYPackages {
+		sv.SetTTY(key, true)
+	}
 
 	log.Info("Attempting to load saved repo URL")
 	savedURL, err := db.GetRepoURL()
@@ -92,7 +101,15 @@ func BubblerMiddleware(sv *supervisor.Supervisor, cfg *config.Config) wish.Middl
 
 		pty, _, active := s.Pty()
 		if !active {
-			wish.Fatalln(s, "no active terminal")
+			// A common slip: 'ssh nixtea' from a script or with -T. Point
+			// at the fix instead of dying with a terse error — the CLI
+			// middleware already handles command sessions without a PTY.
+			fmt.Fprintln(s, "The nixtea dashboard needs a terminal.")
+			fmt.Fprintln(s, "")
+			fmt.Fprintln(s, "Try one of:")
+			fmt.Fprintln(s, "  ssh -t <host>          interactive dashboard")
+			fmt.Fprintln(s, "  ssh <host> pkgs        scripted CLI commands")
+			_ = s.Exit(1)
 			return nil
 		}
 
@@ -102,26 +119,56 @@ func BubblerMiddleware(sv *supervisor.Supervisor, cfg *config.Config) wish.Middl
 				urlInput: savedURL,
 				focused:  true,
 			},
-			width:     pty.Window.Width,
-			height:    pty.Window.Height,
-			term:      pty.Term,
-			db:        db,
-			nixClient: nixClient,
-			program:   nil,
-			sv:        sv,
+			width:       pty.Window.Width,
+			height:      pty.Window.Height,
+			term:        pty.Term,
+			db:          db,
+			nixClient:   nixClient,
+			program:     nil,
+			sv:          sv,
+			cfg:         cfg,
+			fingerprint: sessionFingerprint(s),
 		}
 
 		if savedURL != "" {
 			packages, err := nixClient.GetFormattedPackages(savedURL)
 			if err == nil {
 				m.listState.packages = packages
+				m.listState.labels = packageLabels(db)
 				m.currentPane = PaneList
 
 			}
 		}
 
-		p := newProg(m, append(bubbletea.MakeOptions(s), tea.WithAltScreen())...)
-		sv.AddProgram(p) // Register this program
+		// If this key was here recently, land them back where they were
+		// instead of at the top of the list
+		if snap, ok := recallSession(m.fingerprint); ok && len(m.listState.packages) > 0 {
+			if snap.selectedIndex >= 0 && snap.selectedIndex < len(m.listState.packages) {
+				m.listState.selectedIndex = snap.selectedIndex
+			}
+			if snap.pane == PaneDetail && snap.pkgKey != "" {
+				for _, pkg := range m.listState.packages {
+					if pkg.Key == snap.pkgKey {
+						m.detailState.pkg = pkg
+						for _, text := range sv.RetainedOutput(pkg.Key) {
+							m.detailState.outputLines = append(m.detailState.outputLines, LogLine{
+								Text:      text,
+								Timestamp: time.Now(),
+							})
+						}
+						m.currentPane = PaneDetail
+						break
+					}
+				}
+			}
+		}
+
+		opts := bubbletea.MakeOptions(s)
+		if cfg.Altscreen && termSupportsAltscreen(pty.Term) {
+			opts = append(opts, tea.WithAltScreen())
+		}
+		p := newProg(m, opts...)
+		sv.AddProgramWithDone(p, s.Context().Done()) // Register this program
 
 		// Clean up when the session ends
 		go func() {
@@ -142,10 +189,21 @@ func (m model) Init() tea.Cmd {
 
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
+	// An OSC52 copy escape rides along with exactly one render. By the
+	// time the next message arrives that render has happened, so drop
+	// the escape instead of rewriting the clipboard on every repaint.
+	// (A fresh "c" press below sets it again after this clear.)
+	m.detailState.copySeq = ""
+
 	switch msg := msg.(type) {
 	case timeMsg:
 		m.time = time.Time(msg)
 
+		// Let the copy confirmation fade instead of sticking forever
+		if m.detailState.copyNotice != "" && time.Since(m.detailState.copiedAt) > copyNoticeTimeout {
+			m.detailState.copyNotice = ""
+		}
+
 	case UpdateListFailedMsg:
 		// Show error in status line
 		m.detailState.outputLines = append(m.detailState.outputLines, LogLine{
@@ -159,29 +217,55 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.listState.packages = msg.packages
 		return m, nil
 
+	case supervisor.ProcessStateChangedMsg:
+		// Track the latest status per package so list/detail views
+		// stay accurate without parsing log text
+		if m.listState.statuses == nil {
+			m.listState.statuses = make(map[string]string)
+		}
+		m.listState.statuses[msg.ProcessKey] = msg.Status
+
+		if m.detailState.pkg.Key == msg.ProcessKey {
+			m.detailState.Pid = msg.PID
+			if msg.Status == "stopped" && msg.ExitCode != 0 {
+				text := fmt.Sprintf("Process exited with code %d", msg.ExitCode)
+
+				// A crash right after 'r' means the earlier success
+				// message was misleading — say what actually happened
+				if !m.detailState.startedAt.IsZero() && time.Since(m.detailState.startedAt) < 5*time.Second {
+					text = fmt.Sprintf("Service failed shortly after start (exit code %d)", msg.ExitCode)
+					if last := m.sv.LastOutputLine(msg.ProcessKey); last != "" {
+						text += ": " + last
+					}
+				}
+
+				m.detailState.outputLines = append(m.detailState.outputLines, LogLine{
+					Text:      text,
+					Timestamp: time.Now(),
+				})
+				m.updateLogsViewport()
+			}
+		}
+		return m, nil
+
 	case supervisor.NewLogLineMsg:
 		// Handle log messages at the top level
-		ol := m.detailState.outputLines
-
 		if m.currentPane == PaneDetail {
-			m.detailState.outputLines = append(ol, LogLine{
+			m.detailState.outputLines = append(m.detailState.outputLines, LogLine{
 				Text:      msg.Text,
 				Timestamp: msg.Timestamp,
 			})
 
-			// Sort the lines by timestamp
-			sort.Slice(ol, func(i, j int) bool {
-				return ol[i].Timestamp.Before(ol[j].Timestamp)
+			// Keep lines ordered by timestamp even if broadcasts arrive
+			// slightly out of order
+			sort.Slice(m.detailState.outputLines, func(i, j int) bool {
+				return m.detailState.outputLines[i].Timestamp.Before(m.detailState.outputLines[j].Timestamp)
 			})
 
 			if m.detailState.logsViewport.Height != 0 {
-				// Map the log lines to just their text for display
-				textLines := make([]string, len(ol))
-				for i, line := range ol {
-					textLines[i] = line.Text
-				}
-				m.detailState.logsViewport.SetContent(strings.Join(textLines, "\n"))
-				m.detailState.logsViewport.GotoBottom()
+				// Re-render through the shared path so the errors-only
+				// filter and stderr highlighting survive live appends
+				m.updateLogsViewport()
 			}
 		}
 		return m, nil
@@ -190,6 +274,14 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.height = msg.Height
 		m.width = msg.Width
 
+		// Keep the detail viewport sized to the new window
+		if m.detailState.logsViewport.Width != 0 {
+			logsWidth, _, contentHeight, _ := m.detailLayout()
+			m.detailState.logsViewport.Width = logsWidth - 4
+			m.detailState.logsViewport.Height = contentHeight - 3
+			m.updateLogsViewport()
+		}
+
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "q", "ctrl+c":
@@ -197,18 +289,49 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, tea.Quit
 		}
 
+		var (
+			next tea.Model = m
+			cmd  tea.Cmd
+		)
 		switch m.currentPane {
 		case PaneInput:
-			return m.updateInput(msg)
+			next, cmd = m.updateInput(msg)
 		case PaneList:
-			return m.updateList(msg)
+			next, cmd = m.updateList(msg)
 		case PaneDetail:
-			return m.updateDetail(msg)
+			next, cmd = m.updateDetail(msg)
 		}
+
+		// Keep the reconnect memory current as the user moves around
+		if nm, ok := next.(model); ok {
+			nm.rememberPlace()
+		}
+		return next, cmd
 	}
 	return m, nil
 }
 
+// termSupportsAltscreen filters out terminals known to lack an
+// alternate screen; everything else is assumed capable. Running inline
+// there beats emitting escape codes the terminal will print verbatim.
+func termSupportsAltscreen(term string) bool {
+	switch term {
+	case "", "dumb", "vt52", "vt100":
+		return false
+	}
+	return true
+}
+
+// rememberPlace records the current pane and selection so a reconnecting
+// session can be restored to the same spot
+func (m model) rememberPlace() {
+	rememberSession(m.fingerprint, sessionSnapshot{
+		pane:          m.currentPane,
+		pkgKey:        m.detailState.pkg.Key,
+		selectedIndex: m.listState.selectedIndex,
+	})
+}
+
 func (m model) View() string {
 	switch m.currentPane {
 	case PaneInput:
@@ -229,7 +352,12 @@ func stringOr(s, fallback string) string {
 }
 
 func (m model) viewHeader() string {
-	header := fmt.Sprintf("%s %s",
+	banner := ""
+	if m.cfg != nil && m.cfg.Banner != "" {
+		banner = termenv.String(m.cfg.Banner).Foreground(termenv.ANSIBrightBlack).String() + "\n"
+	}
+
+	header := banner + fmt.Sprintf("%s %s",
 		termenv.String(" nixtea ").Background(termenv.ANSIBrightMagenta).Foreground(termenv.ANSIWhite),
 		termenv.String(stringOr(m.inputState.urlInput, "repo not set")).Foreground(termenv.ANSIBrightBlack))
 
@@ -240,7 +368,15 @@ func (m model) viewHeader() string {
 	return headerStyle.Render(header)
 }
 
+// readOnly reports whether mutating keys (run/stop/update) are disabled
+func (m model) readOnly() bool {
+	return m.cfg != nil && m.cfg.ReadOnly
+}
+
 func (m model) viewFooter(help string) string {
+	if m.readOnly() {
+		help = "(read-only) " + help
+	}
 	return fmt.Sprintf("\n%s",
 		termenv.String(help).Foreground(termenv.ANSIBrightBlack))
 }
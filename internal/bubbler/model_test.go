@@ -0,0 +1,268 @@
+// file: internal/bubbler/model_test.go
+//
+// Drives the dashboard model directly through Update with synthetic
+// tea.Msg values — no SSH session or terminal needed. The supervisor is
+// a real (empty) Supervisor and the store is the in-memory backend, so
+// the tests cover the same wiring a live session gets.
+
+package bubbler
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"walross/nixtea/internal/db"
+	"walross/nixtea/internal/nixapi"
+	"walross/nixtea/internal/supervisor"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// testModel builds a model with three packages loaded, as if a repo URL
+// had already been submitted
+func testModel() model {
+	return model{
+		currentPane: PaneList,
+		inputState:  InputState{urlInput: "github:walross/demo", focused: true},
+		listState: ListState{
+			packages: []nixapi.PackageDisplay{
+				{Name: "Web", Key: "web"},
+				{Name: "API", Key: "api"},
+				{Name: "Worker", Key: "worker"},
+			},
+		},
+		width:  100,
+		height: 40,
+		db:     db.NewMemoryStore(),
+		sv:     supervisor.NewSupervisor(),
+	}
+}
+
+// keyRunes builds the KeyMsg a terminal would produce for typed text
+func keyRunes(s string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+// send runs one message through Update and hands back the concrete model
+func send(t *testing.T, m model, msg tea.Msg) (model, tea.Cmd) {
+	t.Helper()
+	next, cmd := m.Update(msg)
+	nm, ok := next.(model)
+	if !ok {
+		t.Fatalf("Update returned %T, want model", next)
+	}
+	return nm, cmd
+}
+
+func TestListNavigation(t *testing.T) {
+	m := testModel()
+
+	m, _ = send(t, m, keyRunes("j"))
+	m, _ = send(t, m, keyRunes("j"))
+	if m.listState.selectedIndex != 2 {
+		t.Errorf("after j j: selectedIndex = %d, want 2", m.listState.selectedIndex)
+	}
+
+	// Down at the bottom stays put
+	m, _ = send(t, m, tea.KeyMsg{Type: tea.KeyDown})
+	if m.listState.selectedIndex != 2 {
+		t.Errorf("down at bottom moved to %d, want 2", m.listState.selectedIndex)
+	}
+
+	m, _ = send(t, m, tea.KeyMsg{Type: tea.KeyUp})
+	if m.listState.selectedIndex != 1 {
+		t.Errorf("after up: selectedIndex = %d, want 1", m.listState.selectedIndex)
+	}
+
+	m, _ = send(t, m, keyRunes("g"))
+	if m.listState.selectedIndex != 0 {
+		t.Errorf("after g: selectedIndex = %d, want 0", m.listState.selectedIndex)
+	}
+
+	// Up at the top stays put
+	m, _ = send(t, m, keyRunes("k"))
+	if m.listState.selectedIndex != 0 {
+		t.Errorf("up at top moved to %d, want 0", m.listState.selectedIndex)
+	}
+
+	m, _ = send(t, m, keyRunes("G"))
+	if m.listState.selectedIndex != 2 {
+		t.Errorf("after G: selectedIndex = %d, want 2", m.listState.selectedIndex)
+	}
+}
+
+func TestPaneTransitions(t *testing.T) {
+	m := testModel()
+	m.listState.selectedIndex = 1
+
+	// enter on the list opens the detail pane for the selected package
+	m, _ = send(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.currentPane != PaneDetail {
+		t.Fatalf("after enter: pane = %v, want PaneDetail", m.currentPane)
+	}
+	if m.detailState.pkg.Key != "api" {
+		t.Errorf("detail pkg = %q, want api", m.detailState.pkg.Key)
+	}
+
+	// esc walks back: detail → list → input
+	m, _ = send(t, m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.currentPane != PaneList {
+		t.Errorf("after esc from detail: pane = %v, want PaneList", m.currentPane)
+	}
+	m, _ = send(t, m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.currentPane != PaneInput {
+		t.Errorf("after esc from list: pane = %v, want PaneInput", m.currentPane)
+	}
+}
+
+func TestQuitKeys(t *testing.T) {
+	for _, msg := range []tea.KeyMsg{
+		keyRunes("q"),
+		{Type: tea.KeyCtrlC},
+	} {
+		m := testModel()
+		_, cmd := send(t, m, msg)
+		if cmd == nil {
+			t.Errorf("%q did not produce a command, want tea.Quit", msg.String())
+			continue
+		}
+		if _, ok := cmd().(tea.QuitMsg); !ok {
+			t.Errorf("%q produced %T, want tea.QuitMsg", msg.String(), cmd())
+		}
+	}
+}
+
+func TestInputEditing(t *testing.T) {
+	m := testModel()
+	m.currentPane = PaneInput
+	m.inputState.urlInput = ""
+
+	for _, ch := range []string{"a", "b", "c"} {
+		m, _ = send(t, m, keyRunes(ch))
+	}
+	if m.inputState.urlInput != "abc" {
+		t.Errorf("after typing: urlInput = %q, want abc", m.inputState.urlInput)
+	}
+
+	m, _ = send(t, m, tea.KeyMsg{Type: tea.KeyBackspace})
+	if m.inputState.urlInput != "ab" {
+		t.Errorf("after backspace: urlInput = %q, want ab", m.inputState.urlInput)
+	}
+
+	// Backspace on an empty input must not panic or underflow
+	m.inputState.urlInput = ""
+	m, _ = send(t, m, tea.KeyMsg{Type: tea.KeyBackspace})
+	if m.inputState.urlInput != "" {
+		t.Errorf("backspace on empty input produced %q", m.inputState.urlInput)
+	}
+}
+
+func TestLogLineAppendsOnDetailPane(t *testing.T) {
+	m := testModel()
+	m.currentPane = PaneDetail
+	m.detailState.pkg = m.listState.packages[0]
+
+	now := time.Now()
+	m, _ = send(t, m, supervisor.NewLogLineMsg{Text: "hello", Timestamp: now})
+	m, _ = send(t, m, supervisor.NewLogLineMsg{Text: "world", Timestamp: now.Add(time.Second)})
+
+	if len(m.detailState.outputLines) != 2 {
+		t.Fatalf("got %d output lines, want 2", len(m.detailState.outputLines))
+	}
+	if m.detailState.outputLines[0].Text != "hello" || m.detailState.outputLines[1].Text != "world" {
+		t.Errorf("lines = %+v, want hello then world", m.detailState.outputLines)
+	}
+
+	// Out-of-order broadcasts are re-sorted by timestamp
+	m, _ = send(t, m, supervisor.NewLogLineMsg{Text: "earlier", Timestamp: now.Add(-time.Second)})
+	if m.detailState.outputLines[0].Text != "earlier" {
+		t.Errorf("first line = %q, want the earlier timestamp sorted first", m.detailState.outputLines[0].Text)
+	}
+
+	// Lines broadcast while on another pane are not collected
+	m.currentPane = PaneList
+	m, _ = send(t, m, supervisor.NewLogLineMsg{Text: "ignored", Timestamp: now.Add(time.Minute)})
+	if len(m.detailState.outputLines) != 3 {
+		t.Errorf("got %d output lines after list-pane broadcast, want 3", len(m.detailState.outputLines))
+	}
+}
+
+func TestCopyEscapeEmittedOnce(t *testing.T) {
+	m := testModel()
+	m.currentPane = PaneDetail
+	m.detailState.pkg = m.listState.packages[0]
+
+	m, _ = send(t, m, keyRunes("c"))
+	if !strings.HasPrefix(m.detailState.copySeq, "\x1b]52;") {
+		t.Fatalf("copySeq = %q, want an OSC52 escape", m.detailState.copySeq)
+	}
+	if m.detailState.copyNotice == "" {
+		t.Error("copy notice not set after c")
+	}
+
+	// The render carrying the escape has happened by the time the next
+	// message arrives — any later repaint must not rewrite the clipboard
+	m, _ = send(t, m, timeMsg(time.Now()))
+	if m.detailState.copySeq != "" {
+		t.Error("copySeq still set after the next message; clipboard would be rewritten every repaint")
+	}
+
+	// The footer confirmation hangs around briefly, then fades
+	if m.detailState.copyNotice == "" {
+		t.Error("copy notice cleared too early")
+	}
+	m.detailState.copiedAt = time.Now().Add(-2 * copyNoticeTimeout)
+	m, _ = send(t, m, timeMsg(time.Now()))
+	if m.detailState.copyNotice != "" {
+		t.Error("copy notice did not fade after the timeout")
+	}
+
+	// A fresh press re-arms the escape
+	m, _ = send(t, m, keyRunes("c"))
+	if m.detailState.copySeq == "" {
+		t.Error("second c press did not set a new copy escape")
+	}
+}
+
+func TestErrorsOnlyFilter(t *testing.T) {
+	m := testModel()
+	m.currentPane = PaneDetail
+	m.detailState.pkg = m.listState.packages[0]
+	m.detailState.logsViewport = viewport.New(60, 10)
+
+	now := time.Now()
+	for i, text := range []string{"plain output", "[stderr] broken pipe", "another plain line"} {
+		m, _ = send(t, m, supervisor.NewLogLineMsg{Text: text, Timestamp: now.Add(time.Duration(i) * time.Second)})
+	}
+
+	// 'e' toggles the filter and re-renders the viewport through the
+	// shared path
+	m, _ = send(t, m, keyRunes("e"))
+	if !m.detailState.errorsOnly {
+		t.Fatal("errorsOnly not set after e")
+	}
+	content := m.detailState.logsViewport.View()
+	if strings.Contains(content, "plain output") {
+		t.Error("filtered viewport still shows non-error lines")
+	}
+	if !strings.Contains(content, "broken pipe") {
+		t.Error("filtered viewport dropped the stderr line")
+	}
+
+	// A live broadcast while filtered must not resurface non-error lines
+	m, _ = send(t, m, supervisor.NewLogLineMsg{Text: "more plain", Timestamp: now.Add(time.Minute)})
+	if strings.Contains(m.detailState.logsViewport.View(), "more plain") {
+		t.Error("live append bypassed the errors-only filter")
+	}
+
+	// Toggling back restores everything
+	m, _ = send(t, m, keyRunes("e"))
+	if m.detailState.errorsOnly {
+		t.Fatal("errorsOnly still set after second e")
+	}
+	if !strings.Contains(m.detailState.logsViewport.View(), "plain output") {
+		t.Error("unfiltered viewport missing plain lines")
+	}
+}
@@ -0,0 +1,77 @@
+// file: internal/bubbler/sessionmem.go
+//
+// short-lived memory of where a user was in the TUI, keyed by their SSH
+// public key fingerprint, so a dropped connection resumes in place
+// instead of back at the repo input
+
+package bubbler
+
+import (
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/ssh"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+// How long a disconnected user's place is remembered before they start
+// fresh again
+const sessionMemoryTTL = 5 * time.Minute
+
+// sessionSnapshot captures just enough state to land a reconnecting user
+// back where they were: which pane, which package
+type sessionSnapshot struct {
+	pane          Pane
+	pkgKey        string
+	selectedIndex int
+	savedAt       time.Time
+}
+
+var (
+	sessionMemMu sync.Mutex
+	sessionMem   = make(map[string]sessionSnapshot)
+)
+
+// sessionFingerprint identifies the connecting user by public key; empty
+// when the session has no key (e.g. keyboard-interactive auth)
+func sessionFingerprint(s ssh.Session) string {
+	key := s.PublicKey()
+	if key == nil {
+		return ""
+	}
+	return gossh.FingerprintSHA256(key)
+}
+
+// rememberSession records where a user currently is. Called as the model
+// moves around so the latest position is always on file.
+func rememberSession(fingerprint string, snap sessionSnapshot) {
+	if fingerprint == "" {
+		return
+	}
+	snap.savedAt = time.Now()
+
+	sessionMemMu.Lock()
+	sessionMem[fingerprint] = snap
+	sessionMemMu.Unlock()
+}
+
+// recallSession returns the remembered position for a fingerprint if one
+// exists and hasn't gone stale. Expired entries are dropped on the way out.
+func recallSession(fingerprint string) (sessionSnapshot, bool) {
+	if fingerprint == "" {
+		return sessionSnapshot{}, false
+	}
+
+	sessionMemMu.Lock()
+	defer sessionMemMu.Unlock()
+
+	snap, exists := sessionMem[fingerprint]
+	if !exists {
+		return sessionSnapshot{}, false
+	}
+	if time.Since(snap.savedAt) > sessionMemoryTTL {
+		delete(sessionMem, fingerprint)
+		return sessionSnapshot{}, false
+	}
+	return snap, true
+}
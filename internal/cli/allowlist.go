@@ -0,0 +1,97 @@
+// file: internal/cli/allowlist.go
+
+package cli
+
+import (
+	"fmt"
+	"net"
+	"slices"
+	"sync"
+
+	"walross/nixtea/internal/config"
+
+	"github.com/charmbracelet/log"
+	"github.com/charmbracelet/ssh"
+	"github.com/charmbracelet/wish"
+)
+
+// parseAllowlist turns the configured CIDR entries into networks; bare
+// IPs are treated as single-host networks and invalid entries are
+// dropped with a warning
+func parseAllowlist(entries []string) []*net.IPNet {
+	var allowed []*net.IPNet
+	for _, entry := range entries {
+		_, ipNet, err := net.ParseCIDR(entry)
+		if err != nil {
+			if ip := net.ParseIP(entry); ip != nil {
+				bits := 32
+				if ip.To4() == nil {
+					bits = 128
+				}
+				ipNet = &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}
+			} else {
+				log.Warn("Ignoring invalid ALLOWED_CIDRS entry", "entry", entry, "error", err)
+				continue
+			}
+		}
+		allowed = append(allowed, ipNet)
+	}
+	return allowed
+}
+
+// AllowlistMiddleware logs the source address of every connection and,
+// when ALLOWED_CIDRS is set, rejects sessions from outside the listed
+// networks. Defense in depth for internet-exposed instances — key auth
+// still applies either way.
+func AllowlistMiddleware(cfg *config.Config) wish.Middleware {
+	// The allowlist is hot-reloadable via SIGHUP, so re-read it per
+	// connection. The parsed form is cached and only rebuilt when the
+	// entries actually change, keeping the invalid-entry warnings to one
+	// per reload rather than one per connection.
+	var (
+		parseMu     sync.Mutex
+		lastEntries []string
+		allowed     []*net.IPNet
+	)
+
+	return func(next ssh.Handler) ssh.Handler {
+		return func(sess ssh.Session) {
+			remote := sess.RemoteAddr()
+			log.Info("Connection", "remote", remote, "user", sess.User())
+
+			cfg.Reload.RLock()
+			entries := cfg.AllowedCIDRs
+			cfg.Reload.RUnlock()
+
+			parseMu.Lock()
+			if !slices.Equal(entries, lastEntries) {
+				allowed = parseAllowlist(entries)
+				lastEntries = entries
+			}
+			current := allowed
+			parseMu.Unlock()
+
+			if len(current) == 0 {
+				next(sess)
+				return
+			}
+
+			host, _, err := net.SplitHostPort(remote.String())
+			if err != nil {
+				host = remote.String()
+			}
+			ip := net.ParseIP(host)
+
+			for _, ipNet := range current {
+				if ip != nil && ipNet.Contains(ip) {
+					next(sess)
+					return
+				}
+			}
+
+			log.Warn("Rejecting connection: source address not in allowlist", "remote", remote)
+			fmt.Fprintln(sess.Stderr(), "Connections from this address are not permitted")
+			_ = sess.Exit(1)
+		}
+	}
+}
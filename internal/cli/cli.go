@@ -3,22 +3,34 @@
 package cli
 
 import (
+	"bufio"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
 	"sort"
 	"strings"
 	"text/tabwriter"
 	"time"
 	"walross/nixtea/internal/config"
 	"walross/nixtea/internal/db"
+	"walross/nixtea/internal/nixapi"
 	"walross/nixtea/internal/suprvisor"
 
+	"github.com/charmbracelet/keygen"
 	"github.com/charmbracelet/lipgloss/tree"
+	"github.com/charmbracelet/log"
 	"github.com/charmbracelet/ssh"
 	"github.com/charmbracelet/wish"
+	gossh "golang.org/x/crypto/ssh"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
 	"github.com/spf13/cobra"
 )
 
@@ -40,8 +52,8 @@ func titleBlock() string {
 }
 
 // repoUpdateCmd creates the 'repo update' command
-func repoUpdateCmd(db *db.DB, sp *suprvisor.UnderSupervision) *cobra.Command {
-	return &cobra.Command{
+func repoUpdateCmd(db db.Store, sp *suprvisor.UnderSupervision) *cobra.Command {
+	cmd := &cobra.Command{
 		Use:   "update",
 		Short: "Update and rebuild all packages from current repository",
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -54,7 +66,63 @@ func repoUpdateCmd(db *db.DB, sp *suprvisor.UnderSupervision) *cobra.Command {
 			}
 			cmd.Printf("→ Found active repository: %s\n", url)
 
-			err = sp.HydrateWithTimeout(url, 5*time.Minute)
+			// Detached mode: kick the hydrate off in the daemon and hand
+			// back a job id instead of tying up the session for minutes
+			if detach, _ := cmd.Flags().GetBool("detach"); detach {
+				id := sp.StartDetachedHydrate(url, hydrateTimeout(cmd))
+				cmd.Printf("→ Update %s started in the background\n", id)
+				cmd.Printf("  Poll it with: nixtea ctx update status %s\n", id)
+				return nil
+			}
+
+			// Transparency mode: show the nix invocation without running it
+			if printCmd, _ := cmd.Flags().GetBool("print-command"); printCmd {
+				if pkgKey, _ := cmd.Flags().GetString("package"); pkgKey != "" {
+					cmd.Printf("nix build --no-write-lock-file --print-out-paths %s#%s\n", url, pkgKey)
+				} else {
+					cmd.Printf("nix flake show --no-write-lock-file --json %s\n", url)
+					cmd.Printf("nix build --no-write-lock-file --print-out-paths %s#<each package>\n", url)
+				}
+				return nil
+			}
+
+			// A single --package rebuild skips the full hydrate pass
+			if pkgKey, _ := cmd.Flags().GetString("package"); pkgKey != "" {
+				if err := sp.RebuildPackage(url, pkgKey); err != nil {
+					return err
+				}
+				cmd.Printf("\n✓ Package %s built successfully!\n", pkgKey)
+				return nil
+			}
+
+			// Snapshot before the hydrate so we can describe the change set
+			oldSnapshot, snapErr := db.GetHydrateSnapshot(url)
+			if snapErr != nil {
+				cmd.Printf("✗ %v\n", snapErr)
+			}
+
+			err = sp.HydrateWithTimeout(url, hydrateTimeout(cmd))
+
+			// Record the new outcomes and show what changed
+			newSnapshot := currentSnapshot(sp)
+			if len(oldSnapshot) > 0 {
+				cmd.Println()
+				cmd.Print(renderHydrateDiff(oldSnapshot, newSnapshot))
+			}
+			if werr := db.SaveHydrateSnapshot(url, newSnapshot); werr != nil {
+				cmd.Printf("✗ Failed to record hydrate snapshot: %v\n", werr)
+			}
+
+			// Write the machine-readable report regardless of outcome so
+			// CI can inspect partial failures too
+			if reportPath, _ := cmd.Flags().GetString("report"); reportPath != "" {
+				if werr := writeBuildReport(sp, reportPath); werr != nil {
+					cmd.Printf("✗ %v\n", werr)
+				} else {
+					cmd.Printf("→ Build report written to %s\n", reportPath)
+				}
+			}
+
 			if err != nil {
 				// Handle build errors
 				if buildErr, ok := err.(*suprvisor.BuildError); ok {
@@ -80,16 +148,263 @@ func repoUpdateCmd(db *db.DB, sp *suprvisor.UnderSupervision) *cobra.Command {
 			return nil
 		},
 	}
+	cmd.Flags().String("package", "", "rebuild only the specified package key")
+	cmd.Flags().String("report", "", "write a JSON build report to this path")
+	cmd.Flags().Duration("timeout", 5*time.Minute, "how long to wait for the rebuild")
+	cmd.Flags().Bool("print-command", false, "print the nix commands that would run and exit")
+	cmd.Flags().Bool("detach", false, "run the rebuild in the background and return a job id")
+	cmd.AddCommand(repoUpdateStatusCmd(sp))
+	return cmd
 }
 
-// Helper function to create repo add command
-func repoAddCmd(db *db.DB) *cobra.Command {
+// repoUpdateStatusCmd creates the 'update status' command: poll detached
+// update jobs started with --detach
+func repoUpdateStatusCmd(sp *suprvisor.UnderSupervision) *cobra.Command {
+	return &cobra.Command{
+		Use:   "status [job-id]",
+		Short: "Show the status of detached update jobs",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 1 {
+				job, ok := sp.UpdateJobStatus(args[0])
+				if !ok {
+					return fmt.Errorf("no update job %q (run 'nixtea ctx update status' to list jobs)", args[0])
+				}
+				printUpdateJob(cmd, job)
+				return nil
+			}
+
+			jobs := sp.UpdateJobs()
+			if len(jobs) == 0 {
+				cmd.Println("No detached updates have run")
+				return nil
+			}
+			for _, job := range jobs {
+				printUpdateJob(cmd, job)
+			}
+			return nil
+		},
+	}
+}
+
+// printUpdateJob renders one detached update job as a status line
+func printUpdateJob(cmd *cobra.Command, job suprvisor.UpdateJob) {
+	switch job.Status {
+	case "running":
+		cmd.Printf("→ %s running for %s (%s)\n", job.ID, time.Since(job.StartedAt).Round(time.Second), job.RepoURL)
+	case "succeeded":
+		cmd.Printf("✓ %s succeeded in %s (%s)\n", job.ID, job.FinishedAt.Sub(job.StartedAt).Round(time.Second), job.RepoURL)
+	default:
+		cmd.Printf("✗ %s failed after %s: %s\n", job.ID, job.FinishedAt.Sub(job.StartedAt).Round(time.Second), job.Error)
+	}
+}
+
+// currentSnapshot captures the supervisor's per-package outcomes in the
+// shape the hydrate_snapshot table stores
+func currentSnapshot(sp *suprvisor.UnderSupervision) map[string]db.SnapshotEntry {
+	items := sp.GetSupervised()
+	entries := make(map[string]db.SnapshotEntry, len(items))
+	for key, item := range items {
+		entries[key] = db.SnapshotEntry{
+			StorePath: item.StorePath,
+			Status:    item.Status,
+		}
+	}
+	return entries
+}
+
+// renderHydrateDiff compares two snapshots and describes what changed:
+// added/removed packages, changed outputs, and new build failures
+func renderHydrateDiff(old, current map[string]db.SnapshotEntry) string {
+	var added, removed, changed, failed []string
+
+	for key, entry := range current {
+		prev, existed := old[key]
+		switch {
+		case !existed:
+			added = append(added, key)
+		case entry.Status == "build_failed" && prev.Status != "build_failed":
+			failed = append(failed, key)
+		case entry.StorePath != prev.StorePath:
+			changed = append(changed, key)
+		}
+	}
+	for key := range old {
+		if _, exists := current[key]; !exists {
+			removed = append(removed, key)
+		}
+	}
+
+	sort.Strings(added)
+	sort.Strings(removed)
+	sort.Strings(changed)
+	sort.Strings(failed)
+
+	if len(added)+len(removed)+len(changed)+len(failed) == 0 {
+		return "No changes since the last hydrate.\n"
+	}
+
+	var out strings.Builder
+	for _, key := range added {
+		fmt.Fprintf(&out, "+ %s (new)\n", key)
+	}
+	for _, key := range removed {
+		fmt.Fprintf(&out, "- %s (removed)\n", key)
+	}
+	for _, key := range changed {
+		fmt.Fprintf(&out, "~ %s (new store path)\n", key)
+	}
+	for _, key := range failed {
+		fmt.Fprintf(&out, "✗ %s (newly failing)\n", key)
+	}
+	return out.String()
+}
+
+// pkgsDiffCmd creates the 'pkgs diff' command comparing current state
+// against the last recorded hydrate snapshot
+func pkgsDiffCmd(db db.Store, sp *suprvisor.UnderSupervision) *cobra.Command {
+	return &cobra.Command{
+		Use:   "diff",
+		Short: "Show what changed since the last recorded hydrate",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			url, err := resolveRepoURL(cmd, db)
+			if err != nil {
+				return fmt.Errorf("failed to get repository URL: %w", err)
+			}
+			if url == "" {
+				return fmt.Errorf("no repository set. Use 'nixtea ctx add' to set a repository")
+			}
+
+			old, err := db.GetHydrateSnapshot(url)
+			if err != nil {
+				return err
+			}
+			if len(old) == 0 {
+				cmd.Println("No hydrate snapshot recorded yet. Run 'nixtea repos update' first.")
+				return nil
+			}
+
+			cmd.Print(renderHydrateDiff(old, currentSnapshot(sp)))
+			return nil
+		},
+	}
+}
+
+// writeBuildReport serializes the results of the last hydrate pass to a
+// JSON file so external tooling can gate on the build outcome
+func writeBuildReport(sp *suprvisor.UnderSupervision, path string) error {
+	report := sp.LastBuildReport()
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal build report: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write build report: %w", err)
+	}
+	return nil
+}
+
+// repoImportCmd creates the 'repos import' command: bulk-add
+// newline-delimited repo URLs from a file (or stdin with '-')
+func repoImportCmd(db db.Store) *cobra.Command {
+	return &cobra.Command{
+		Use:   "import <file>",
+		Short: "Import repositories from a newline-delimited file",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var data []byte
+			var err error
+			if args[0] == "-" {
+				data, err = io.ReadAll(cmd.InOrStdin())
+			} else {
+				data, err = os.ReadFile(args[0])
+			}
+			if err != nil {
+				return fmt.Errorf("failed to read import file: %w", err)
+			}
+
+			added := 0
+			skipped := 0
+			for _, line := range strings.Split(string(data), "\n") {
+				line = strings.TrimSpace(line)
+				if line == "" || strings.HasPrefix(line, "#") {
+					continue
+				}
+
+				// First token is the URL; anything after it is ignored
+				url := strings.Fields(line)[0]
+				if _, err := db.SaveRepo(url); err != nil {
+					cmd.Printf("✗ %s: %v\n", url, err)
+					skipped++
+					continue
+				}
+				added++
+			}
+
+			cmd.Printf("✓ Imported %d repositories (%d skipped)\n", added, skipped)
+			return nil
+		},
+	}
+}
+
+// repoExportCmd creates the 'repos export' command: dump saved repo URLs
+// in the same newline-delimited format 'repos import' reads
+func repoExportCmd(db db.Store) *cobra.Command {
 	return &cobra.Command{
+		Use:   "export",
+		Short: "Export saved repositories, one URL per line",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repos, err := db.GetRepos()
+			if err != nil {
+				return fmt.Errorf("failed to list repositories: %w", err)
+			}
+
+			for _, repo := range repos {
+				cmd.Println(repo.URL)
+			}
+			return nil
+		},
+	}
+}
+
+// Helper function to create repo add command
+func repoAddCmd(db db.Store) *cobra.Command {
+	cmd := &cobra.Command{
 		Use:   "add [url]",
 		Short: "Add a new repository",
-		Args:  cobra.ExactArgs(1),
+		Args:  cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			url := args[0]
+			var url string
+
+			file, _ := cmd.Flags().GetString("file")
+			switch {
+			case file != "":
+				// Read the URL from a file for scripted setups
+				data, err := os.ReadFile(file)
+				if err != nil {
+					return fmt.Errorf("failed to read URL from %s: %w", file, err)
+				}
+				url = strings.TrimSpace(string(data))
+
+			case len(args) == 1 && args[0] == "-":
+				// Read the URL from stdin: echo url | ssh nixtea repos add -
+				data, err := io.ReadAll(cmd.InOrStdin())
+				if err != nil {
+					return fmt.Errorf("failed to read URL from stdin: %w", err)
+				}
+				url = strings.TrimSpace(string(data))
+
+			case len(args) == 1:
+				url = args[0]
+
+			default:
+				return fmt.Errorf("provide a URL, '-' for stdin, or --file")
+			}
+
+			if url == "" {
+				return fmt.Errorf("repository URL is empty")
+			}
+
 			repo, err := db.SaveRepo(url)
 			if err != nil {
 				return fmt.Errorf("failed to save repository: %w", err)
@@ -98,10 +413,48 @@ func repoAddCmd(db *db.DB) *cobra.Command {
 			return nil
 		},
 	}
+	cmd.Flags().String("file", "", "read the repository URL from a file")
+	return cmd
 }
 
-func pkgRunCmd(cfg *config.Config, db *db.DB, sp *suprvisor.UnderSupervision) *cobra.Command {
-	return &cobra.Command{
+// hydrateTimeout returns the hydrate timeout for a command, honoring a
+// --timeout flag when one is registered and set
+func hydrateTimeout(cmd *cobra.Command) time.Duration {
+	if t, err := cmd.Flags().GetDuration("timeout"); err == nil && t > 0 {
+		return t
+	}
+	return 5 * time.Minute
+}
+
+// resolveRepoURL returns the repository URL a package command should
+// operate on, honoring the persistent --repo override and falling back
+// to the active repository. An override must already be saved in the DB.
+func resolveRepoURL(cmd *cobra.Command, db db.Store) (string, error) {
+	override, err := cmd.Flags().GetString("repo")
+	if err != nil || override == "" {
+		return db.GetRepoURL()
+	}
+
+	repos, err := db.GetRepos()
+	if err != nil {
+		return "", fmt.Errorf("failed to get repositories: %w", err)
+	}
+	for _, repo := range repos {
+		if repo.URL == override {
+			return repo.URL, nil
+		}
+	}
+
+	return "", fmt.Errorf("repository %s not found. Use 'nixtea repos add' to add it first", override)
+}
+
+func pkgRunCmd(cfg *config.Config, db db.Store, sp *suprvisor.UnderSupervision) *cobra.Command {
+	var replicas int
+	var after []string
+	var wait bool
+	var foreground bool
+	var printCommand bool
+	cmd := &cobra.Command{
 		Use:   "run [package]",
 		Short: "Run a package",
 		Args:  cobra.ExactArgs(1),
@@ -109,7 +462,7 @@ func pkgRunCmd(cfg *config.Config, db *db.DB, sp *suprvisor.UnderSupervision) *c
 			pkgKey := args[0]
 
 			// Get current repository URL (needed if we have to hydrate)
-			url, err := db.GetRepoURL()
+			url, err := resolveRepoURL(cmd, db)
 			if err != nil {
 				return fmt.Errorf("failed to get repository URL: %w", err)
 			}
@@ -120,30 +473,317 @@ func pkgRunCmd(cfg *config.Config, db *db.DB, sp *suprvisor.UnderSupervision) *c
 			// If supervisor has no items, hydrate it first
 			if !sp.HasItems() {
 				cmd.Printf("→ Loading package state...\n")
-				if err := sp.HydrateWithTimeout(url, 5*time.Minute); err != nil {
+				if err := sp.HydrateWithTimeout(url, hydrateTimeout(cmd)); err != nil {
 					return fmt.Errorf("failed to hydrate supervisor: %w", err)
 				}
 			}
 
+			// Transparency mode: show the exact command without running it
+			if printCommand {
+				line, err := sp.CommandLine(pkgKey)
+				if err != nil {
+					return err
+				}
+				cmd.Println(line)
+				return nil
+			}
+
+			// Record declared dependencies and make sure they're up first
+			if len(after) > 0 {
+				if err := sp.SetDependencies(pkgKey, after); err != nil {
+					return err
+				}
+				for _, dep := range after {
+					if err := waitForRunning(cmd, sp, dep); err != nil {
+						return err
+					}
+				}
+			}
+
 			// Run the package
-			cmd.Printf("→ Starting package %s...\n", pkgKey)
-			if err := sp.Run(pkgKey); err != nil {
+			if replicas > 1 {
+				cmd.Printf("→ Starting package %s (%d replicas)...\n", pkgKey, replicas)
+			} else {
+				cmd.Printf("→ Starting package %s...\n", pkgKey)
+			}
+			if err := sp.RunReplicas(pkgKey, replicas); err != nil {
+				if wait {
+					// Deploy gates need the failure on the exit status
+					return fmt.Errorf("failed to start package: %w", err)
+				}
 				cmd.Printf("✗ Failed to start package: %v\n", err)
 				return nil // Return nil to avoid double error message
 			}
 
+			// Foreground mode: stay attached, stream the combined output
+			// inline and propagate the child's exit code
+			if foreground {
+				output, err := sp.StreamOutput(pkgKey)
+				if err != nil {
+					return err
+				}
+
+				go func() {
+					_, _ = io.Copy(cmd.OutOrStdout(), output)
+				}()
+
+				waitDone := make(chan struct{})
+				var exitCode int
+				go func() {
+					exitCode, _ = sp.Wait(pkgKey)
+					close(waitDone)
+				}()
+
+				select {
+				case <-waitDone:
+					// Let the log copy drain what the scanners buffered
+					time.Sleep(100 * time.Millisecond)
+					if exitCode != 0 {
+						return &exitCodeError{code: exitCode, msg: fmt.Sprintf("package %s exited with code %d", pkgKey, exitCode)}
+					}
+					cmd.Printf("\n✓ Package %s exited cleanly\n", pkgKey)
+					return nil
+				case <-cmd.Context().Done():
+					if closer, ok := output.(io.Closer); ok {
+						closer.Close()
+					}
+					cmd.Println("\ndetached (process still running)")
+					return nil
+				}
+			}
+
+			// Block until the process proves it can stay up, so scripted
+			// callers can gate on the exit status
+			if wait {
+				cmd.Printf("→ Waiting for %s to settle...\n", pkgKey)
+				if err := waitForStable(cmd, sp, pkgKey, hydrateTimeout(cmd)); err != nil {
+					return err
+				}
+			}
+
 			cmd.Printf("✓ Package %s is now running\n\n", pkgKey)
 			cmd.Printf("To check package status:\n")
 			cmd.Printf("  nixtea pkgs status %s\n\n", pkgKey)
 			cmd.Printf("To view package logs:\n")
 			cmd.Printf("  nixtea pkgs logs %s\n", pkgKey)
 
+			return nil
+		},
+	}
+	cmd.Flags().IntVar(&replicas, "replicas", 1, "number of instances to start")
+	cmd.Flags().StringSliceVar(&after, "after", nil, "packages that must be running before this one starts")
+	cmd.Flags().BoolVar(&wait, "wait", false, "block until the package is stably running; exit non-zero if it fails")
+	cmd.Flags().BoolVar(&foreground, "foreground", false, "stay attached, stream output inline and propagate the exit code")
+	cmd.Flags().BoolVar(&printCommand, "print-command", false, "print the command that would be executed and exit")
+	return cmd
+}
+
+// waitForStable blocks until the package has stayed running for a short
+// grace period, or fails if it stops or never comes up within the
+// timeout. This is what makes 'pkgs run --wait' usable as a CI gate.
+func waitForStable(cmd *cobra.Command, sp *suprvisor.UnderSupervision, key string, timeout time.Duration) error {
+	const grace = 3 * time.Second
+
+	deadline := time.Now().Add(timeout)
+	var runningSince time.Time
+	var wasStarting bool
+
+	for time.Now().Before(deadline) {
+		status, err := sp.Status(key)
+		if err != nil {
+			return err
+		}
+
+		switch status {
+		case "running":
+			if runningSince.IsZero() {
+				runningSince = time.Now()
+			}
+			if time.Since(runningSince) >= grace {
+				return nil
+			}
+		case "starting":
+			// Still inside the package's startup grace window
+			wasStarting = true
+		case "build_failed":
+			return fmt.Errorf("package %s failed to build", key)
+		default:
+			// It was up and went down again — an early crash
+			if !runningSince.IsZero() || wasStarting {
+				return fmt.Errorf("package %s exited shortly after start (check 'nixtea pkgs logs %s')", key, key)
+			}
+		}
+
+		select {
+		case <-cmd.Context().Done():
+			return cmd.Context().Err()
+		case <-time.After(200 * time.Millisecond):
+		}
+	}
+
+	return fmt.Errorf("package %s did not reach a stable running state within %s", key, timeout)
+}
+
+// waitForRunning starts a dependency if needed and waits until it
+// reports running
+func waitForRunning(cmd *cobra.Command, sp *suprvisor.UnderSupervision, key string) error {
+	status, err := sp.Status(key)
+	if err != nil {
+		return err
+	}
+
+	if status != "running" {
+		cmd.Printf("→ Starting dependency %s...\n", key)
+		if err := sp.Run(key); err != nil {
+			return fmt.Errorf("failed to start dependency %s: %w", key, err)
+		}
+	}
+
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		status, err := sp.Status(key)
+		if err != nil {
+			return err
+		}
+		if status == "running" {
+			return nil
+		}
+		select {
+		case <-cmd.Context().Done():
+			return cmd.Context().Err()
+		case <-time.After(200 * time.Millisecond):
+		}
+	}
+
+	return fmt.Errorf("dependency %s did not reach running state", key)
+}
+
+// Color palette for --all log prefixes, cycled through as packages first
+// speak so each gets a stable, distinguishable color
+// ansiEscapePattern matches terminal escape sequences (CSI and OSC) so
+// app color output can be stripped for files and non-terminal consumers
+var ansiEscapePattern = regexp.MustCompile(`\x1b(\[[0-9;?]*[ -/]*[@-~]|\][^\x07\x1b]*(\x07|\x1b\\))`)
+
+// stripAnsi removes terminal escape sequences from a log line. The log
+// path itself retains lines byte-for-byte as the app wrote them, so
+// stripping is purely a presentation choice at output time.
+func stripAnsi(line string) string {
+	return ansiEscapePattern.ReplaceAllString(line, "")
+}
+
+var multiplexColors = []string{"12", "10", "11", "13", "14", "9"}
+
+// streamAllLogs multiplexes every running package's log stream onto the
+// session, prefixing each line with a color-coded package key, docker
+// compose style. Packages that start or stop mid-stream are handled by
+// the supervisor's rescan.
+func streamAllLogs(cmd *cobra.Command, cfg *config.Config, sp *suprvisor.UnderSupervision, grepRe *regexp.Regexp, timestamps bool, stripColor bool) error {
+	lines, cancel := sp.StreamAllOutput()
+	defer cancel()
+
+	cmd.Printf("→ Streaming logs from all running packages (Ctrl-C to detach)\n\n")
+
+	// Pad prefixes to the widest key seen so columns stay aligned
+	keyStyles := make(map[string]lipgloss.Style)
+	widest := 0
+
+	for {
+		select {
+		case line := <-lines:
+			if stripColor {
+				line.Text = stripAnsi(line.Text)
+			}
+			if grepRe != nil && !grepRe.MatchString(line.Text) {
+				continue
+			}
+
+			style, ok := keyStyles[line.Key]
+			if !ok {
+				color := multiplexColors[len(keyStyles)%len(multiplexColors)]
+				style = lipgloss.NewStyle().Foreground(lipgloss.Color(color))
+				keyStyles[line.Key] = style
+			}
+			if len(line.Key) > widest {
+				widest = len(line.Key)
+			}
+
+			prefix := fmt.Sprintf("%-*s |", widest, line.Key)
+			text := line.Text
+			if timestamps {
+				text = cfg.FormatTimestamp(time.Now()) + " " + text
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%s %s\n", style.Render(prefix), text)
+		case <-cmd.Context().Done():
+			cmd.Println("\ndetached (processes still running)")
+			return nil
+		}
+	}
+}
+
+// pkgsUpCmd creates the 'pkgs up' command: start every package in
+// dependency order
+func pkgsUpCmd(db db.Store, sp *suprvisor.UnderSupervision) *cobra.Command {
+	return &cobra.Command{
+		Use:   "up",
+		Short: "Start all packages in dependency order",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			url, err := resolveRepoURL(cmd, db)
+			if err != nil {
+				return fmt.Errorf("failed to get repository URL: %w", err)
+			}
+			if url == "" {
+				return fmt.Errorf("no repository set. Use 'nixtea ctx add' to set a repository")
+			}
+
+			// If supervisor has no items, hydrate it first
+			if !sp.HasItems() {
+				cmd.Printf("→ Loading package state...\n")
+				if err := sp.HydrateWithTimeout(url, hydrateTimeout(cmd)); err != nil {
+					return fmt.Errorf("failed to hydrate supervisor: %w", err)
+				}
+			}
+
+			order, err := sp.StartOrder()
+			if err != nil {
+				return err
+			}
+
+			for _, key := range order {
+				status, err := sp.Status(key)
+				if err != nil {
+					return err
+				}
+				if status == "running" {
+					cmd.Printf("• %s already running\n", key)
+					continue
+				}
+				if status == "build_failed" {
+					cmd.Printf("✗ %s skipped (build failed)\n", key)
+					continue
+				}
+
+				// Dependencies precede us in the order, but make sure
+				// they actually came up before starting this one
+				for _, dep := range sp.Dependencies(key) {
+					if err := waitForRunning(cmd, sp, dep); err != nil {
+						return err
+					}
+				}
+
+				cmd.Printf("→ Starting %s...\n", key)
+				if err := sp.Run(key); err != nil {
+					cmd.Printf("✗ Failed to start %s: %v\n", key, err)
+					continue
+				}
+				cmd.Printf("✓ %s running\n", key)
+			}
+
 			return nil
 		},
 	}
 }
 
-func pkgsStopCmd(sp *suprvisor.UnderSupervision, db *db.DB) *cobra.Command {
+func pkgsStopCmd(sp *suprvisor.UnderSupervision, db db.Store) *cobra.Command {
 	return &cobra.Command{
 		Use:   "stop [package]",
 		Short: "Stop a running package",
@@ -152,7 +792,7 @@ func pkgsStopCmd(sp *suprvisor.UnderSupervision, db *db.DB) *cobra.Command {
 			pkgKey := args[0]
 
 			// Get current repository URL
-			url, err := db.GetRepoURL()
+			url, err := resolveRepoURL(cmd, db)
 			if err != nil {
 				return fmt.Errorf("failed to get repository URL: %w", err)
 			}
@@ -163,7 +803,7 @@ func pkgsStopCmd(sp *suprvisor.UnderSupervision, db *db.DB) *cobra.Command {
 			// If supervisor has no items, hydrate it first
 			if !sp.HasItems() {
 				cmd.Printf("→ Loading package state...\n")
-				if err := sp.HydrateWithTimeout(url, 5*time.Minute); err != nil {
+				if err := sp.HydrateWithTimeout(url, hydrateTimeout(cmd)); err != nil {
 					return fmt.Errorf("failed to hydrate supervisor: %w", err)
 				}
 			}
@@ -184,14 +824,419 @@ func pkgsStopCmd(sp *suprvisor.UnderSupervision, db *db.DB) *cobra.Command {
 	}
 }
 
-func NewRootCmd(cfg *config.Config, db *db.DB, sp *suprvisor.UnderSupervision) *cobra.Command {
-	rootCmd := &cobra.Command{
-		Use:   "nixtea",
-		Short: "Nixtea is a Nix package runner",
-	}
+// pkgsInspectCmd creates the 'pkgs inspect' command showing store path,
+// binary, derivation name, and closure size for a built package
+func pkgsInspectCmd(cfg *config.Config, db db.Store, sp *suprvisor.UnderSupervision) *cobra.Command {
+	return &cobra.Command{
+		Use:   "inspect [package]",
+		Short: "Show store path, binary, and closure size for a package",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			pkgKey := args[0]
 
-	outputStyle := lipgloss.NewStyle().
-		PaddingTop(1).
+			// Get current repository URL
+			url, err := resolveRepoURL(cmd, db)
+			if err != nil {
+				return fmt.Errorf("failed to get repository URL: %w", err)
+			}
+			if url == "" {
+				return fmt.Errorf("no repository set. Use 'nixtea ctx add' to set a repository")
+			}
+
+			// If supervisor has no items, hydrate it first
+			if !sp.HasItems() {
+				cmd.Printf("→ Loading package state...\n")
+				if err := sp.HydrateWithTimeout(url, hydrateTimeout(cmd)); err != nil {
+					return fmt.Errorf("failed to hydrate supervisor: %w", err)
+				}
+			}
+
+			items := sp.GetSupervised()
+			item, exists := items[pkgKey]
+			if !exists {
+				return fmt.Errorf("package %s not found", pkgKey)
+			}
+
+			if item.BinaryPath == "" {
+				return fmt.Errorf("package %s has not been built. Use 'nixtea repos update' to build it", pkgKey)
+			}
+
+			// The binary lives under <store-path>/bin/<name>
+			storePath := item.StorePath
+			if storePath == "" {
+				storePath = filepath.Dir(filepath.Dir(item.BinaryPath))
+			}
+
+			client := nixapi.NewClient(nixapi.CurrentSystem())
+			defer client.Close()
+
+			var status strings.Builder
+			w := tabwriter.NewWriter(&status, 0, 0, 2, ' ', 0)
+			fmt.Fprintf(w, "Name:\t%s\n", item.Name)
+			fmt.Fprintf(w, "Key:\t%s\n", pkgKey)
+			fmt.Fprintf(w, "Derivation:\t%s\n", nixapi.DerivationName(storePath))
+			fmt.Fprintf(w, "Store Path:\t%s\n", storePath)
+			fmt.Fprintf(w, "Binary:\t%s\n", item.BinaryPath)
+
+			if size, err := client.GetClosureSize(storePath); err != nil {
+				fmt.Fprintf(w, "Closure Size:\tunavailable (%v)\n", err)
+			} else {
+				fmt.Fprintf(w, "Closure Size:\t%s\n", formatBytes(size))
+			}
+			w.Flush()
+
+			headerStyle := lipgloss.NewStyle().
+				Foreground(lipgloss.Color("99")).
+				Bold(true).
+				PaddingBottom(1)
+
+			cmd.Printf("%s\n%s", headerStyle.Render("Package Inspect"), status.String())
+			return nil
+		},
+	}
+}
+
+// pkgsEnvCmd creates the 'pkgs env' command showing the environment a
+// package's process was launched with, secrets redacted
+func pkgsEnvCmd(sp *suprvisor.UnderSupervision) *cobra.Command {
+	return &cobra.Command{
+		Use:   "env [package]",
+		Short: "Show the resolved runtime environment of a package",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			pkgKey := args[0]
+
+			env, err := sp.GetEnv(pkgKey)
+			if err != nil {
+				return err
+			}
+
+			headerStyle := lipgloss.NewStyle().
+				Foreground(lipgloss.Color("99")).
+				Bold(true).
+				PaddingBottom(1)
+
+			cmd.Println(headerStyle.Render(fmt.Sprintf("Environment for %s", pkgKey)))
+			for _, kv := range env {
+				cmd.Println(kv)
+			}
+			return nil
+		},
+	}
+}
+
+// pkgsErrorsCmd creates the 'pkgs errors' command listing build_failed
+// packages with their stored error output
+func pkgsErrorsCmd(sp *suprvisor.UnderSupervision) *cobra.Command {
+	return &cobra.Command{
+		Use:   "errors [package]",
+		Short: "Show build errors for failed packages",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			failures := sp.BuildFailures()
+
+			// A specific package was requested
+			if len(args) == 1 {
+				pkgKey := args[0]
+				detail, exists := failures[pkgKey]
+				if !exists {
+					cmd.Printf("Package %s has no build failure recorded\n", pkgKey)
+					return nil
+				}
+				cmd.Printf("✗ %s\n\n%s\n", pkgKey, detail)
+				return nil
+			}
+
+			if len(failures) == 0 {
+				cmd.Println("No packages are currently in build_failed state.")
+				return nil
+			}
+
+			keys := make([]string, 0, len(failures))
+			for key := range failures {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+
+			failStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+			for _, key := range keys {
+				cmd.Printf("%s %s\n  %s\n\n", failStyle.Render("✗"), key, failures[key])
+			}
+			cmd.Println("Run 'nixtea repos update' to retry the builds.")
+			return nil
+		},
+	}
+}
+
+// pkgsOpenCmd creates the 'pkgs open' command: detect a running
+// package's listening port and show how to forward it over the nixtea
+// connection
+func pkgsOpenCmd(sp *suprvisor.UnderSupervision) *cobra.Command {
+	return &cobra.Command{
+		Use:   "open <package>",
+		Short: "Detect a package's listening port and how to reach it",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			pkgKey := args[0]
+
+			port, err := sp.DetectPort(pkgKey)
+			if err != nil {
+				return err
+			}
+			if port == 0 {
+				return fmt.Errorf("no listening TCP port detected for %s", pkgKey)
+			}
+
+			cmd.Printf("✓ %s is listening on port %d\n\n", pkgKey, port)
+			cmd.Println("The server accepts direct-tcpip forwards; reach it from your machine with:")
+			cmd.Printf("  ssh -L %d:localhost:%d <nixtea-host>\n", port, port)
+			return nil
+		},
+	}
+}
+
+// pkgsEventsCmd creates the 'pkgs events' command: the recent lifecycle
+// history (starts, stops, crashes, builds) for one package
+func pkgsEventsCmd(sp *suprvisor.UnderSupervision) *cobra.Command {
+	return &cobra.Command{
+		Use:   "events <package>",
+		Short: "Show recent lifecycle events for a package",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			pkgKey := args[0]
+
+			history, err := sp.EventHistory(pkgKey)
+			if err != nil {
+				return err
+			}
+			if len(history) == 0 {
+				cmd.Printf("No events recorded for %s yet\n", pkgKey)
+				return nil
+			}
+
+			crashStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+			for _, evt := range history {
+				line := fmt.Sprintf("%s  %s", evt.Timestamp.Format("2006-01-02 15:04:05"), evt.Type)
+				if evt.PID != 0 {
+					line += fmt.Sprintf(" (pid %d)", evt.PID)
+				}
+				if evt.Detail != "" {
+					line += ": " + evt.Detail
+				}
+				if evt.Type == "crashed" || evt.Type == "build_failed" {
+					line = crashStyle.Render(line)
+				}
+				cmd.Println(line)
+			}
+			return nil
+		},
+	}
+}
+
+// pkgsTopCmd creates the 'pkgs top' command: a continuously-refreshing
+// table of CPU and memory usage for every running package
+func pkgsTopCmd(sp *suprvisor.UnderSupervision) *cobra.Command {
+	return &cobra.Command{
+		Use:   "top",
+		Short: "Live resource usage for all running packages",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			for {
+				items := sp.GetSupervised()
+
+				type sample struct {
+					key     string
+					name    string
+					pid     int
+					metrics *suprvisor.ProcessMetrics
+				}
+
+				samples := make([]sample, 0, len(items))
+				for key, item := range items {
+					if item.Status != "running" {
+						continue
+					}
+					metrics, err := sp.GetMetrics(key)
+					if err != nil {
+						continue
+					}
+					samples = append(samples, sample{key, item.Name, item.Pid, metrics})
+				}
+
+				// Highest CPU first
+				sort.Slice(samples, func(i, j int) bool {
+					return samples[i].metrics.CPUPercent > samples[j].metrics.CPUPercent
+				})
+
+				// Clear screen and move cursor home before redrawing
+				cmd.Print("\x1b[2J\x1b[H")
+
+				var buf strings.Builder
+				w := tabwriter.NewWriter(&buf, 0, 0, 3, ' ', 0)
+				fmt.Fprintln(w, "KEY\tPID\tCPU\tMEM")
+				for _, sm := range samples {
+					fmt.Fprintf(w, "%s\t%d\t%.1f%%\t%.1f MB\n",
+						sm.key, sm.pid, sm.metrics.CPUPercent, sm.metrics.MemoryKB/1024)
+				}
+				w.Flush()
+
+				if len(samples) == 0 {
+					cmd.Println("No packages are currently running.")
+				} else {
+					cmd.Print(buf.String())
+				}
+				cmd.Println("\nRefreshing every 2s — press Ctrl-C or disconnect to exit")
+
+				select {
+				case <-cmd.Context().Done():
+					return nil
+				case <-time.After(2 * time.Second):
+				}
+			}
+		},
+	}
+}
+
+// formatBytes renders a byte count in a human-readable unit
+func formatBytes(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
+
+// eventsCmd creates the 'events' command: a newline-delimited JSON
+// stream of supervisor events until the client disconnects
+func eventsCmd(sp *suprvisor.UnderSupervision) *cobra.Command {
+	return &cobra.Command{
+		Use:   "events",
+		Short: "Stream supervisor events as newline-delimited JSON",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			events, cancel := sp.SubscribeEvents()
+			defer cancel()
+
+			enc := json.NewEncoder(cmd.OutOrStdout())
+			for {
+				select {
+				case evt, ok := <-events:
+					if !ok {
+						return nil
+					}
+					if err := enc.Encode(evt); err != nil {
+						return nil
+					}
+				case <-cmd.Context().Done():
+					return nil
+				}
+			}
+		},
+	}
+}
+
+// doctorCheck is one pass/fail line in the doctor report
+type doctorCheck struct {
+	name   string
+	ok     bool
+	detail string
+}
+
+// doctorCmd creates the 'doctor' command, a self-service diagnostic for
+// the most common environment problems new users hit
+func doctorCmd(cfg *config.Config, db db.Store) *cobra.Command {
+	return &cobra.Command{
+		Use:   "doctor",
+		Short: "Diagnose the nixtea environment",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var checks []doctorCheck
+
+			// nix present + version
+			if out, err := exec.Command("nix", "--version").Output(); err != nil {
+				checks = append(checks, doctorCheck{"nix installed", false, "nix not found in PATH"})
+			} else {
+				checks = append(checks, doctorCheck{"nix installed", true, strings.TrimSpace(string(out))})
+			}
+
+			// flakes / nix-command enabled
+			if out, err := exec.Command("nix", "config", "show", "experimental-features").Output(); err != nil {
+				checks = append(checks, doctorCheck{"flakes enabled", false, "could not read nix config"})
+			} else {
+				features := strings.TrimSpace(string(out))
+				ok := strings.Contains(features, "flakes") && strings.Contains(features, "nix-command")
+				checks = append(checks, doctorCheck{"flakes enabled", ok, "experimental-features = " + features})
+			}
+
+			// DB directory writable
+			probe := filepath.Join(cfg.DBDir, ".nixtea-doctor")
+			if err := os.WriteFile(probe, []byte("ok"), 0644); err != nil {
+				checks = append(checks, doctorCheck{"DB dir writable", false, fmt.Sprintf("%s: %v", cfg.DBDir, err)})
+			} else {
+				os.Remove(probe)
+				checks = append(checks, doctorCheck{"DB dir writable", true, cfg.DBDir})
+			}
+
+			// Host key present and readable
+			if _, err := os.Open(cfg.HostKeyPath); err != nil {
+				checks = append(checks, doctorCheck{"host key readable", false, fmt.Sprintf("%s: %v", cfg.HostKeyPath, err)})
+			} else {
+				checks = append(checks, doctorCheck{"host key readable", true, cfg.HostKeyPath})
+			}
+
+			// Active repo resolvable
+			url, err := db.GetRepoURL()
+			if err != nil || url == "" {
+				checks = append(checks, doctorCheck{"active repo", false, "no repository set (use 'nixtea repos add')"})
+			} else if err := exec.Command("nix", "flake", "metadata", url, "--no-write-lock-file").Run(); err != nil {
+				checks = append(checks, doctorCheck{"active repo", false, fmt.Sprintf("%s: nix flake metadata failed", url)})
+			} else {
+				checks = append(checks, doctorCheck{"active repo", true, url})
+			}
+
+			// Render the report
+			passStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
+			failStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+
+			failed := 0
+			for _, check := range checks {
+				mark := passStyle.Render("✓")
+				if !check.ok {
+					mark = failStyle.Render("✗")
+					failed++
+				}
+				cmd.Printf("%s %-18s %s\n", mark, check.name, check.detail)
+			}
+
+			if failed > 0 {
+				cmd.Printf("\n%d of %d checks failed\n", failed, len(checks))
+			} else {
+				cmd.Printf("\nAll %d checks passed\n", len(checks))
+			}
+			return nil
+		},
+	}
+}
+
+func NewRootCmd(cfg *config.Config, db db.Store, sp *suprvisor.UnderSupervision) *cobra.Command {
+	rootCmd := &cobra.Command{
+		Use:   "nixtea",
+		Short: "Nixtea is a Nix package runner",
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			// Drop all styling when asked to (or when the NO_COLOR
+			// convention is set) so piped output stays readable
+			noColor, _ := cmd.Flags().GetBool("no-color")
+			if noColor || os.Getenv("NO_COLOR") != "" {
+				lipgloss.SetColorProfile(termenv.Ascii)
+			}
+		},
+	}
+	rootCmd.PersistentFlags().Bool("no-color", false, "disable colored output")
+
+	outputStyle := lipgloss.NewStyle().
+		PaddingTop(1).
 		PaddingLeft(2)
 
 	// repos - list
@@ -221,192 +1266,828 @@ func NewRootCmd(cfg *config.Config, db *db.DB, sp *suprvisor.UnderSupervision) *
 		},
 	}
 
-	// Add subcommands to ctx command
-	reposCmd.AddCommand(repoAddCmd(db), repoUpdateCmd(db, sp))
+	// Add subcommands to ctx command
+	reposCmd.AddCommand(repoAddCmd(db), repoUpdateCmd(db, sp), repoImportCmd(db), repoExportCmd(db))
+
+	// help command
+	helpCmd := &cobra.Command{
+		Use:   "help",
+		Short: "Show nixtea help",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.Println("nixtea - A Nix package runner and manager")
+			cmd.Println("\nUsage:")
+			cmd.Println("  ssh nixtea <command>")
+			cmd.Println("\nCommands:")
+			cmd.Println("  repos                  List, add, or set active repositories")
+			cmd.Println("  pkgs                   List packages from active repository")
+			cmd.Println("  pkgs run    <pkg-key>     Start a package")
+			cmd.Println("  pkgs stop   <pkg-key>    Stop a running package")
+			cmd.Println("  pkgs status <pkg-key>  Show package status and metrics")
+			cmd.Println("  pkgs logs   <pkg-key>    Stream package logs (ESC to quit)")
+			cmd.Println("  doctor                 Diagnose the nixtea environment")
+			cmd.Println("  help                   Show this help message")
+			return nil
+		},
+	}
+
+	pkgsCmd := pkgsListCmd(cfg, db, sp)
+	pkgsCmd.Short = "Package management commands"
+	pkgsCmd.PersistentFlags().String("repo", "", "operate on a specific saved repository instead of the active one")
+	pkgsCmd.PersistentFlags().Duration("timeout", 5*time.Minute, "how long to wait when packages need to be built")
+
+	// Add this to NewRootCmd, alongside the other commands
+	var watchStatus bool
+	var statusRepoAll bool
+	pkgsStatusCmd := &cobra.Command{
+		Use:   "status [package]",
+		Short: "Show status of running packages",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Everything currently in the supervisor, regardless of which
+			// repo it came from — surfaces packages still running after a
+			// context switch
+			if statusRepoAll {
+				items := sp.GetSupervised()
+				if len(items) == 0 {
+					cmd.Println("No packages are currently being supervised.")
+					return nil
+				}
+
+				keys := make([]string, 0, len(items))
+				for key := range items {
+					keys = append(keys, key)
+				}
+				sort.Strings(keys)
+
+				w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+				fmt.Fprintln(w, "KEY\tSTATUS\tPID\tREPO")
+				for _, key := range keys {
+					item := items[key]
+					pid := "-"
+					if item.Pid != 0 {
+						pid = fmt.Sprintf("%d", item.Pid)
+					}
+					repo := item.RepoURL
+					if repo == "" {
+						repo = "-"
+					}
+					fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", key, item.Status, pid, repo)
+				}
+				return w.Flush()
+			}
+
+			// Get current repository URL
+			url, err := resolveRepoURL(cmd, db)
+			if err != nil {
+				return fmt.Errorf("failed to get repository URL: %w", err)
+			}
+			if url == "" {
+				return fmt.Errorf("no repository set. Use 'nixtea ctx add' to set a repository")
+			}
+
+			// If supervisor has no items, hydrate it first
+			if !sp.HasItems() {
+				cmd.Printf("→ Loading package state...\n")
+				if err := sp.HydrateWithTimeout(url, hydrateTimeout(cmd)); err != nil {
+					return fmt.Errorf("failed to hydrate supervisor: %w", err)
+				}
+			}
+
+			items := sp.GetSupervised()
+
+			// If no packages are being supervised
+			if len(items) == 0 {
+				cmd.Println("No packages are currently being supervised.")
+				return nil
+			}
+
+			// If a specific package is requested
+			if len(args) > 0 {
+				pkgKey := args[0]
+				item, exists := items[pkgKey]
+				if !exists {
+					return fmt.Errorf("package %s not found", pkgKey)
+				}
+
+				// Print detailed status for the specific package
+				status := formatPackageStatus(pkgKey, item)
+
+				// Best effort: show the listening port when one is found
+				if port, err := sp.DetectPort(pkgKey); err == nil && port != 0 {
+					status += fmt.Sprintf("  Port: %d\n", port)
+				}
+
+				// Add a header
+				headerStyle := lipgloss.NewStyle().
+					Foreground(lipgloss.Color("99")).
+					Bold(true).
+					PaddingBottom(1)
+
+				cmd.Printf("%s\n%s",
+					headerStyle.Render("Package Status"),
+					status)
+				return nil
+			}
+
+			// Watch mode: redraw the table in place until the
+			// session disconnects
+			if watchStatus {
+				for {
+					// Clear screen and move cursor home
+					cmd.Print("\x1b[2J\x1b[H")
+					cmd.Print(renderStatusOverview(sp.GetSupervised()))
+					cmd.Println("\nRefreshing every 2s — press Ctrl-C or disconnect to exit")
+
+					select {
+					case <-cmd.Context().Done():
+						return nil
+					case <-time.After(2 * time.Second):
+					}
+				}
+			}
+
+			// Otherwise, show status of all packages once
+			cmd.Print(renderStatusOverview(items))
+
+			// Add help text at the bottom
+			cmd.Println("\nFor detailed status of a specific package:")
+			cmd.Printf("  nixtea pkgs status <package>\n")
+
+			return nil
+		},
+	}
+	pkgsStatusCmd.Flags().BoolVarP(&watchStatus, "watch", "w", false, "refresh the status table in place every few seconds")
+	pkgsStatusCmd.Flags().BoolVar(&statusRepoAll, "repo-all", false, "list everything in the supervisor across all repos")
+
+	var logsGrep string
+	var logsAll bool
+	var logsTimestamps bool
+	var logsOutputFile string
+	var logsJSON bool
+	var logsSinceStart bool
+	var logsColor string
+	pkgsLogsCmd := &cobra.Command{
+		Use:   "logs [package]",
+		Short: "Stream logs from a package",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !logsAll && len(args) != 1 {
+				return fmt.Errorf("specify a package key or use --all")
+			}
+
+			// --color: "always" passes app ANSI through byte-for-byte,
+			// "never" strips it, and "auto" (the default) strips only
+			// for file output, where raw escapes are just noise
+			var stripColor bool
+			switch logsColor {
+			case "always":
+			case "never":
+				stripColor = true
+			case "auto":
+				stripColor = logsOutputFile != ""
+			default:
+				return fmt.Errorf("invalid --color value %q (use always, auto or never)", logsColor)
+			}
+
+			// Compile the server-side filter up front so a bad pattern
+			// fails before we touch the stream
+			var grepRe *regexp.Regexp
+			if logsGrep != "" {
+				var err error
+				grepRe, err = regexp.Compile(logsGrep)
+				if err != nil {
+					return fmt.Errorf("invalid --grep pattern: %w", err)
+				}
+			}
+
+			// Get current repository URL
+			url, err := resolveRepoURL(cmd, db)
+			if err != nil {
+				return fmt.Errorf("failed to get repository URL: %w", err)
+			}
+			if url == "" {
+				return fmt.Errorf("no repository set. Use 'nixtea ctx add' to set a repository")
+			}
+
+			// If supervisor has no items, hydrate it first
+			if !sp.HasItems() {
+				cmd.Printf("→ Loading package state...\n")
+				if err := sp.HydrateWithTimeout(url, hydrateTimeout(cmd)); err != nil {
+					return fmt.Errorf("failed to hydrate supervisor: %w", err)
+				}
+			}
+
+			if logsAll {
+				return streamAllLogs(cmd, cfg, sp, grepRe, logsTimestamps, stripColor)
+			}
+
+			pkgKey := args[0]
+
+			// Only the current run: dump what the most recent start has
+			// produced so far and exit — prior runs' output is skipped
+			if logsSinceStart {
+				lines, err := sp.RetainedLogsSinceStart(pkgKey)
+				if err != nil {
+					return err
+				}
+				for _, line := range lines {
+					if stripColor {
+						line = stripAnsi(line)
+					}
+					if grepRe != nil && !grepRe.MatchString(line) {
+						continue
+					}
+					if logsTimestamps {
+						line = cfg.FormatTimestamp(time.Now()) + " " + line
+					}
+					fmt.Fprintln(cmd.OutOrStdout(), line)
+				}
+				return nil
+			}
+
+			// Structured mode: one JSON object per retained line, for log
+			// shippers. Stream origin and capture time come from the
+			// buffer rather than being re-parsed out of the text.
+			if logsJSON {
+				records, err := sp.RetainedRecords(pkgKey)
+				if err != nil {
+					return err
+				}
+
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				for _, rec := range records {
+					if stripColor {
+						rec.Text = stripAnsi(rec.Text)
+					}
+					if grepRe != nil && !grepRe.MatchString(rec.Text) {
+						continue
+					}
+					if err := enc.Encode(map[string]string{
+						"ts":      rec.Time.Format(time.RFC3339Nano),
+						"stream":  rec.Stream,
+						"package": pkgKey,
+						"text":    rec.Text,
+					}); err != nil {
+						return err
+					}
+				}
+				return nil
+			}
+
+			// Snapshot mode: dump the retained buffer to a file on the
+			// server instead of streaming to the terminal
+			if logsOutputFile != "" {
+				lines, err := sp.RetainedLogs(pkgKey)
+				if err != nil {
+					return err
+				}
+
+				var buf strings.Builder
+				written := 0
+				for _, line := range lines {
+					if stripColor {
+						line = stripAnsi(line)
+					}
+					if grepRe != nil && !grepRe.MatchString(line) {
+						continue
+					}
+					buf.WriteString(line)
+					buf.WriteByte('\n')
+					written++
+				}
+
+				if err := os.WriteFile(logsOutputFile, []byte(buf.String()), 0644); err != nil {
+					return fmt.Errorf("failed to write log snapshot: %w", err)
+				}
+				cmd.Printf("✓ Wrote %d lines to %s\n", written, logsOutputFile)
+				return nil
+			}
+
+			// Note when we're showing retained logs rather than a live stream
+			if status, err := sp.Status(pkgKey); err == nil && status != "running" {
+				cmd.Printf("→ Package %s is not running; showing retained logs\n\n", pkgKey)
+			}
+
+			// Get the output stream
+			output, err := sp.StreamOutput(pkgKey)
+			if err != nil {
+				return err
+			}
+
+			// Copy the stream in a goroutine so an interrupt (Ctrl-C /
+			// session close) detaches cleanly instead of blocking in
+			// io.Copy. We never signal the child here — stopping a
+			// package requires 'pkgs stop'.
+			copyDone := make(chan error, 1)
+			go func() {
+				if grepRe == nil && !logsTimestamps && !stripColor {
+					_, err := io.Copy(cmd.OutOrStdout(), output)
+					copyDone <- err
+					return
+				}
+
+				// Filter server-side so only matching lines cross the
+				// SSH connection; timestamps reflect when the line was
+				// received here
+				scanner := bufio.NewScanner(output)
+				for scanner.Scan() {
+					line := scanner.Text()
+					if stripColor {
+						line = stripAnsi(line)
+					}
+					if grepRe != nil && !grepRe.MatchString(line) {
+						continue
+					}
+					if logsTimestamps {
+						line = cfg.FormatTimestamp(time.Now()) + " " + line
+					}
+					fmt.Fprintln(cmd.OutOrStdout(), line)
+				}
+				copyDone <- scanner.Err()
+			}()
+
+			select {
+			case err := <-copyDone:
+				return err
+			case <-cmd.Context().Done():
+				// Stop the replay goroutine feeding the pipe, if any
+				if closer, ok := output.(io.Closer); ok {
+					closer.Close()
+				}
+				cmd.Println("\ndetached (process still running)")
+				return nil
+			}
+		},
+	}
+
+	pkgsLogsCmd.Flags().StringVar(&logsGrep, "grep", "", "only show lines matching this regular expression")
+	pkgsLogsCmd.Flags().BoolVar(&logsAll, "all", false, "multiplex logs from every running package")
+	pkgsLogsCmd.Flags().BoolVar(&logsTimestamps, "timestamps", false, "prefix each line with a timestamp (see TIMESTAMP_FORMAT)")
+	pkgsLogsCmd.Flags().StringVar(&logsOutputFile, "output-file", "", "write the retained log buffer to this server-side path instead of streaming")
+	pkgsLogsCmd.Flags().BoolVar(&logsSinceStart, "since-start", false, "only show output from the current (or most recent) run")
+	pkgsLogsCmd.Flags().BoolVar(&logsJSON, "json", false, "dump the retained buffer as NDJSON ({ts, stream, package, text} per line) and exit")
+	pkgsLogsCmd.Flags().StringVar(&logsColor, "color", "auto", "handle ANSI color in app output: always (pass through), never (strip), auto (strip for --output-file)")
+
+	pkgsCmd.AddCommand(pkgRunCmd(cfg, db, sp), pkgsStatusCmd, pkgsStopCmd(sp, db), pkgsLogsCmd, pkgsInspectCmd(cfg, db, sp), pkgsEnvCmd(sp), pkgsTopCmd(sp), pkgsErrorsCmd(sp), pkgsEventsCmd(sp), pkgsOpenCmd(sp), pkgsRenameCmd(db, sp), pkgsGcCmd(cfg, sp), pkgsFindCmd(db), pkgsPidsCmd(sp), pkgsVerifyCmd(db), pkgsDescribeCmd(sp), pkgsUpCmd(db, sp), pkgsDiffCmd(db, sp))
+
+	// Add all commands to root
+	rootCmd.AddCommand(reposCmd)
+	rootCmd.AddCommand(pkgsCmd)
+	rootCmd.AddCommand(stateCmd(sp))
+	rootCmd.AddCommand(helpCmd)
+	rootCmd.AddCommand(doctorCmd(cfg, db))
+	rootCmd.AddCommand(eventsCmd(sp))
+	rootCmd.AddCommand(hostkeyCmd(cfg))
+
+	return rootCmd
+}
+
+// hostkeyCmd creates the 'hostkey' command group: admin operations on
+// the SSH host key
+func hostkeyCmd(cfg *config.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "hostkey",
+		Short: "Manage the SSH host key",
+	}
+
+	rotate := &cobra.Command{
+		Use:   "rotate",
+		Short: "Generate a fresh host key, backing up the old one",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := cfg.HostKeyPath
+
+			// Keep the old key around so the rotation can be undone
+			if _, err := os.Stat(path); err == nil {
+				backup := fmt.Sprintf("%s.bak-%s", path, time.Now().Format("20060102-150405"))
+				if err := os.Rename(path, backup); err != nil {
+					return fmt.Errorf("failed to back up old host key: %w", err)
+				}
+				// The public half is best effort; it can be regenerated
+				// from the private key anyway
+				_ = os.Rename(path+".pub", backup+".pub")
+				cmd.Printf("→ Old host key backed up to %s\n", backup)
+			}
+
+			kp, err := keygen.New(path, keygen.WithKeyType(keygen.Ed25519), keygen.WithWrite())
+			if err != nil {
+				return fmt.Errorf("failed to generate host key: %w", err)
+			}
+
+			log.Info("Rotated SSH host key", "path", path, "fingerprint", gossh.FingerprintSHA256(kp.PublicKey()))
+			cmd.Printf("✓ New host key written to %s\n", path)
+			cmd.Printf("  Fingerprint: %s\n", gossh.FingerprintSHA256(kp.PublicKey()))
+			cmd.Println("\nClients will see a host-key-changed warning on their next connect.")
+			cmd.Println("Restart nixtea for the new key to take effect.")
+			return nil
+		},
+	}
+
+	cmd.AddCommand(rotate)
+	return cmd
+}
+
+// pkgsDescribeCmd is the one-shot "everything about this package" view:
+// metadata, live status and resource usage, lifecycle history and the
+// tail of the log buffer in a single report
+func pkgsDescribeCmd(sp *suprvisor.UnderSupervision) *cobra.Command {
+	const logTail = 20
+
+	return &cobra.Command{
+		Use:   "describe <key>",
+		Short: "Show metadata, status, and recent logs for a package",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key := args[0]
+
+			items := sp.GetSupervised()
+			item, exists := items[key]
+			if !exists {
+				return fmt.Errorf("package %s not found", key)
+			}
+
+			headerStyle := lipgloss.NewStyle().
+				Foreground(lipgloss.Color("99")).
+				Bold(true)
+
+			var out strings.Builder
+			w := tabwriter.NewWriter(&out, 0, 0, 2, ' ', 0)
+			fmt.Fprintf(w, "Name:\t%s\n", displayName(item))
+			fmt.Fprintf(w, "Key:\t%s\n", key)
+			fmt.Fprintf(w, "Status:\t%s\n", item.Status)
+			if item.Pid > 0 {
+				fmt.Fprintf(w, "PID:\t%d\n", item.Pid)
+			}
+			if item.Replicas > 1 {
+				fmt.Fprintf(w, "Replicas:\t%d\n", item.Replicas)
+			}
+			if !item.StartedAt.IsZero() {
+				fmt.Fprintf(w, "Uptime:\t%s\n", time.Since(item.StartedAt).Round(time.Second))
+			}
+			if item.Restarts > 0 {
+				fmt.Fprintf(w, "Restarts:\t%d\n", item.Restarts)
+			}
+			if item.BinaryPath != "" {
+				fmt.Fprintf(w, "Binary:\t%s\n", item.BinaryPath)
+			}
+			if item.StorePath != "" {
+				fmt.Fprintf(w, "Store Path:\t%s\n", item.StorePath)
+			}
+			if item.LastError != "" {
+				fmt.Fprintf(w, "Last Error:\t%s\n", item.LastError)
+			}
+			if item.BuildError != "" {
+				fmt.Fprintf(w, "Build Error:\t%s\n", item.BuildError)
+			}
+
+			// Resource sample, best effort: a stopped package just skips it
+			if metrics, err := sp.GetMetrics(key); err == nil {
+				fmt.Fprintf(w, "CPU:\t%.1f%%\n", metrics.CPUPercent)
+				fmt.Fprintf(w, "Memory:\t%.1f MB\n", metrics.MemoryKB/1024)
+			}
+			w.Flush()
+
+			cmd.Printf("%s\n%s", headerStyle.Render(fmt.Sprintf("Package: %s", key)), out.String())
 
-	// help command
-	helpCmd := &cobra.Command{
-		Use:   "help",
-		Short: "Show nixtea help",
-		RunE: func(cmd *cobra.Command, args []string) error {
-			cmd.Println("nixtea - A Nix package runner and manager")
-			cmd.Println("\nUsage:")
-			cmd.Println("  ssh nixtea <command>")
-			cmd.Println("\nCommands:")
-			cmd.Println("  repos                  List, add, or set active repositories")
-			cmd.Println("  pkgs                   List packages from active repository")
-			cmd.Println("  pkgs run    <pkg-key>     Start a package")
-			cmd.Println("  pkgs stop   <pkg-key>    Stop a running package")
-			cmd.Println("  pkgs status <pkg-key>  Show package status and metrics")
-			cmd.Println("  pkgs logs   <pkg-key>    Stream package logs (ESC to quit)")
-			cmd.Println("  help                   Show this help message")
+			// Tail of the retained buffer
+			lines, err := sp.RetainedLogs(key)
+			if err == nil && len(lines) > 0 {
+				if len(lines) > logTail {
+					lines = lines[len(lines)-logTail:]
+				}
+				cmd.Printf("\n%s\n", headerStyle.Render(fmt.Sprintf("Last %d log lines", len(lines))))
+				for _, line := range lines {
+					cmd.Println("  " + line)
+				}
+			}
 			return nil
 		},
 	}
+}
 
-	pkgsCmd := &cobra.Command{
-		Use:   "pkgs",
-		Short: "Package management commands",
-		RunE:  pkgsListCmd(cfg, db, sp).RunE,
-	}
-
-	// Add this to NewRootCmd, alongside the other commands
-	pkgsStatusCmd := &cobra.Command{
-		Use:   "status [package]",
-		Short: "Show status of running packages",
+// pkgsVerifyCmd checks a package's reproducibility by rebuilding it
+// from scratch and comparing against the existing output. Useful for
+// flake authors making sure their builds are deterministic before
+// deploying.
+func pkgsVerifyCmd(database db.Store) *cobra.Command {
+	return &cobra.Command{
+		Use:   "verify <key>",
+		Short: "Rebuild a package and check the output is reproducible",
+		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			// Get current repository URL
-			url, err := db.GetRepoURL()
+			key := args[0]
+
+			url, err := resolveRepoURL(cmd, database)
 			if err != nil {
 				return fmt.Errorf("failed to get repository URL: %w", err)
 			}
 			if url == "" {
-				return fmt.Errorf("no repository set. Use 'nixtea ctx add' to set a repository")
+				return fmt.Errorf("no repository set. Use 'nixtea ctx' to set a repository")
 			}
 
-			// If supervisor has no items, hydrate it first
-			if !sp.HasItems() {
-				cmd.Printf("→ Loading package state...\n")
-				if err := sp.HydrateWithTimeout(url, 5*time.Minute); err != nil {
-					return fmt.Errorf("failed to hydrate supervisor: %w", err)
-				}
+			client := nixapi.NewClient(nixapi.CurrentSystem())
+			defer client.Close()
+
+			cmd.Printf("→ Rebuilding %s from scratch, this can take a while...\n", key)
+			if err := client.VerifyPkg(url, key); err != nil {
+				cmd.Printf("✗ %s: %v\n", key, err)
+				return fmt.Errorf("verification failed")
 			}
 
-			items := sp.GetSupervised()
+			cmd.Printf("✓ %s rebuilt identically — output is reproducible\n", key)
+			return nil
+		},
+	}
+}
 
-			// If no packages are being supervised
-			if len(items) == 0 {
-				cmd.Println("No packages are currently being supervised.")
+// pkgsPidsCmd lists the PIDs of supervised processes, one per line, in
+// a shape external watchdogs can consume directly
+func pkgsPidsCmd(sp *suprvisor.UnderSupervision) *cobra.Command {
+	return &cobra.Command{
+		Use:   "pids",
+		Short: "List PIDs of running packages",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			pids := sp.GetRunningPIDs()
+			if len(pids) == 0 {
+				cmd.Println("No packages are running")
 				return nil
 			}
 
-			// If a specific package is requested
-			if len(args) > 0 {
-				pkgKey := args[0]
-				item, exists := items[pkgKey]
-				if !exists {
-					return fmt.Errorf("package %s not found", pkgKey)
-				}
-
-				// Print detailed status for the specific package
-				status := formatPackageStatus(pkgKey, item)
-
-				// Add a header
-				headerStyle := lipgloss.NewStyle().
-					Foreground(lipgloss.Color("99")).
-					Bold(true).
-					PaddingBottom(1)
+			keys := make([]string, 0, len(pids))
+			for key := range pids {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
 
-				cmd.Printf("%s\n%s",
-					headerStyle.Render("Package Status"),
-					status)
-				return nil
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "KEY\tPID")
+			for _, key := range keys {
+				fmt.Fprintf(w, "%s\t%d\n", key, pids[key])
 			}
+			return w.Flush()
+		},
+	}
+}
 
-			// Otherwise, show status of all packages
-			headerStyle := lipgloss.NewStyle().
-				Foreground(lipgloss.Color("99")).
-				Bold(true).
-				PaddingBottom(1)
+// stateDumpJSON is one package's entry in 'nixtea state dump'
+type stateDumpJSON struct {
+	Name          string  `json:"name"`
+	Key           string  `json:"key"`
+	Label         string  `json:"label,omitempty"`
+	Status        string  `json:"status"`
+	Pid           int     `json:"pid"`
+	Replicas      int     `json:"replicas"`
+	BinaryPath    string  `json:"binaryPath"`
+	StorePath     string  `json:"storePath"`
+	RepoURL       string  `json:"repoUrl,omitempty"`
+	StartedAt     string  `json:"startedAt,omitempty"`
+	UptimeSeconds float64 `json:"uptimeSeconds,omitempty"`
+	Restarts      int     `json:"restarts"`
+	LastError     string  `json:"lastError,omitempty"`
+	BuildError    string  `json:"buildError,omitempty"`
+}
 
-			cmd.Println(headerStyle.Render("Package Status Overview"))
-			cmd.Println()
+// stateCmd groups daemon-state introspection commands
+func stateCmd(sp *suprvisor.UnderSupervision) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "state",
+		Short: "Inspect daemon state",
+	}
 
-			// Create a tabwriter for aligned output
-			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
-			fmt.Fprintf(w, "PACKAGE\tSTATUS\tPID\n")
+	// 'state dump' is the everything-the-daemon-tracks view for external
+	// dashboards: broader than 'pkgs status -o json', which only covers
+	// the active repo
+	dump := &cobra.Command{
+		Use:   "dump",
+		Short: "Serialize the full supervisor state as JSON",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			items := sp.GetSupervised()
 
-			// Sort keys for consistent output
-			var keys []string
-			for k := range items {
-				keys = append(keys, k)
+			keys := make([]string, 0, len(items))
+			for key := range items {
+				keys = append(keys, key)
 			}
 			sort.Strings(keys)
 
-			// Print each package's status
+			list := make([]stateDumpJSON, 0, len(keys))
 			for _, key := range keys {
 				item := items[key]
+				entry := stateDumpJSON{
+					Name:       item.Name,
+					Key:        key,
+					Label:      item.Label,
+					Status:     item.Status,
+					Pid:        item.Pid,
+					Replicas:   item.Replicas,
+					BinaryPath: item.BinaryPath,
+					StorePath:  item.StorePath,
+					RepoURL:    item.RepoURL,
+					Restarts:   item.Restarts,
+					LastError:  item.LastError,
+					BuildError: item.BuildError,
+				}
+				if !item.StartedAt.IsZero() {
+					entry.StartedAt = item.StartedAt.Format(time.RFC3339)
+					entry.UptimeSeconds = time.Since(item.StartedAt).Seconds()
+				}
+				list = append(list, entry)
+			}
+
+			data, err := json.MarshalIndent(list, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to marshal state: %w", err)
+			}
+			cmd.Println(string(data))
+			return nil
+		},
+	}
+
+	cmd.AddCommand(dump)
+	return cmd
+}
+
+// pkgsFindCmd searches package names and keys across every saved
+// repository — a discovery tool for users juggling many contexts who
+// forget which repo a package lives in
+func pkgsFindCmd(database db.Store) *cobra.Command {
+	return &cobra.Command{
+		Use:   "find <substring>",
+		Short: "Search packages across all saved repositories",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			needle := strings.ToLower(args[0])
+
+			repos, err := database.GetRepos()
+			if err != nil {
+				return fmt.Errorf("failed to get repositories: %w", err)
+			}
+			if len(repos) == 0 {
+				return fmt.Errorf("no repositories saved. Use 'nixtea repos add' first")
+			}
+
+			client := nixapi.NewClient(nixapi.CurrentSystem())
+			defer client.Close()
+
+			found := 0
+			for _, repo := range repos {
+				packages, err := client.GetSystemPackages(repo.URL)
+				if err != nil {
+					cmd.Printf("✗ %s: %v\n", repo.URL, err)
+					continue
+				}
 
-				// Style the status
-				var statusStyle lipgloss.Style
-				switch item.Status {
-				case "running":
-					statusStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
-				case "stopped":
-					statusStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("203"))
-				default:
-					statusStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("243"))
+				var keys []string
+				for key, pkg := range packages {
+					if strings.Contains(strings.ToLower(key), needle) ||
+						strings.Contains(strings.ToLower(pkg.Name), needle) {
+						keys = append(keys, key)
+					}
+				}
+				if len(keys) == 0 {
+					continue
 				}
+				sort.Strings(keys)
 
-				pid := "-"
-				if item.Pid > 0 {
-					pid = fmt.Sprintf("%d", item.Pid)
+				cmd.Printf("%s\n", repo.URL)
+				for _, key := range keys {
+					cmd.Printf("  %s %s\n", packages[key].Name, hashStyle.Render("#"+key))
+					cmd.Printf("    → nixtea pkgs run %s --repo %s\n", key, repo.URL)
 				}
+				found += len(keys)
+			}
 
-				fmt.Fprintf(w, "%s\t%s\t%s\n",
-					item.Name,
-					statusStyle.Render(item.Status),
-					pid)
+			if found == 0 {
+				cmd.Printf("No packages matching %q\n", args[0])
 			}
-			w.Flush()
+			return nil
+		},
+	}
+}
 
-			// Add help text at the bottom
-			cmd.Println("\nFor detailed status of a specific package:")
-			cmd.Printf("  nixtea pkgs status <package>\n")
+// pkgsGcCmd removes GC roots for packages that are no longer part of
+// the flake, letting nix reclaim their store paths. Roots for tracked
+// packages are left alone so running services keep their binaries.
+func pkgsGcCmd(cfg *config.Config, sp *suprvisor.UnderSupervision) *cobra.Command {
+	return &cobra.Command{
+		Use:   "gc",
+		Short: "Remove build roots for packages no longer in the flake",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			rootsDir := sp.GCRootsDir()
+			if rootsDir == "" {
+				rootsDir = filepath.Join(cfg.DataDir, "gcroots")
+			}
+
+			keys, err := nixapi.ListGCRoots(rootsDir)
+			if err != nil {
+				return err
+			}
+			if len(keys) == 0 {
+				cmd.Println("No GC roots registered")
+				return nil
+			}
+
+			items := sp.GetSupervised()
+
+			removed, kept := 0, 0
+			for _, key := range keys {
+				if _, tracked := items[key]; tracked {
+					kept++
+					continue
+				}
+				if err := nixapi.RemoveGCRoot(rootsDir, key); err != nil {
+					return fmt.Errorf("failed to remove root for %s: %w", key, err)
+				}
+				cmd.Printf("✓ removed stale root %s\n", key)
+				removed++
+			}
 
+			cmd.Printf("Removed %d stale root(s), kept %d\n", removed, kept)
+			if removed > 0 {
+				cmd.Println("Run 'nix-collect-garbage' to reclaim the store paths")
+			}
 			return nil
 		},
 	}
+}
 
-	pkgsLogsCmd := &cobra.Command{
-		Use:   "logs [package]",
-		Short: "Stream logs from a package",
-		Args:  cobra.ExactArgs(1),
+// pkgsRenameCmd gives a package a friendly display label, persisted in
+// the package_config table so it survives restarts. Handy when flake
+// output names are cryptic.
+func pkgsRenameCmd(database db.Store, sp *suprvisor.UnderSupervision) *cobra.Command {
+	return &cobra.Command{
+		Use:   "rename <key> <label>",
+		Short: "Give a package a friendly display name",
+		Args:  cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			pkgKey := args[0]
+			key, label := args[0], args[1]
 
-			// Get current repository URL
-			url, err := db.GetRepoURL()
+			repos, err := database.GetRepos()
 			if err != nil {
-				return fmt.Errorf("failed to get repository URL: %w", err)
+				return fmt.Errorf("failed to get repositories: %w", err)
 			}
-			if url == "" {
-				return fmt.Errorf("no repository set. Use 'nixtea ctx add' to set a repository")
+			var repoID string
+			for _, repo := range repos {
+				if repo.Active {
+					repoID = repo.ID
+					break
+				}
+			}
+			if repoID == "" {
+				return fmt.Errorf("no repository set. Use 'nixtea ctx' to set a repository")
 			}
 
-			// If supervisor has no items, hydrate it first
-			if !sp.HasItems() {
-				cmd.Printf("→ Loading package state...\n")
-				if err := sp.HydrateWithTimeout(url, 5*time.Minute); err != nil {
-					return fmt.Errorf("failed to hydrate supervisor: %w", err)
+			// Merge the label into any existing run configuration rather
+			// than clobbering args/env/replicas
+			var runCfg suprvisor.RunConfig
+			if raw, err := database.GetPackageConfig(repoID, key); err != nil {
+				return err
+			} else if raw != "" {
+				if err := json.Unmarshal([]byte(raw), &runCfg); err != nil {
+					return fmt.Errorf("invalid config for %s: %w", key, err)
 				}
 			}
+			runCfg.Label = label
 
-			// Get the output stream
-			output, err := sp.StreamOutput(pkgKey)
+			data, err := json.Marshal(runCfg)
 			if err != nil {
+				return fmt.Errorf("failed to marshal config: %w", err)
+			}
+			if err := database.SetPackageConfig(repoID, key, string(data)); err != nil {
 				return err
 			}
 
-			// Copy the output stream to stdout
-			_, err = io.Copy(cmd.OutOrStdout(), output)
-			return err
+			// Best effort: update the live supervisor state too so the
+			// label shows up without waiting for the next hydrate
+			if err := sp.SetLabel(key, label); err != nil {
+				log.Debug("Package not in supervisor yet, label applies on next hydrate", "key", key)
+			}
 
+			cmd.Printf("✓ %s will now display as %q\n", key, label)
+			return nil
 		},
 	}
+}
 
-	pkgsCmd.AddCommand(pkgRunCmd(cfg, db, sp), pkgsStatusCmd, pkgsStopCmd(sp, db), pkgsLogsCmd)
-
-	// Add all commands to root
-	rootCmd.AddCommand(reposCmd)
-	rootCmd.AddCommand(pkgsCmd)
-	rootCmd.AddCommand(helpCmd)
+// displayName returns the package's friendly label when one has been
+// set via 'pkgs rename', falling back to the flake's package name
+func displayName(item suprvisor.ItemState) string {
+	if item.Label != "" {
+		return item.Label
+	}
+	return item.Name
+}
 
-	return rootCmd
+// packageGroup derives a display group for a package key: the prefix
+// before the first dash, or "other" for keys without one
+func packageGroup(key string) string {
+	if idx := strings.Index(key, "-"); idx > 0 {
+		return key[:idx]
+	}
+	return "other"
 }
 
-// formatPackagesTreeFromState creates a tree view from supervisor state
-func formatPackagesTreeFromState(sp *suprvisor.UnderSupervision) string {
+// formatPackagesTreeFromState creates a tree view from supervisor state.
+// When grouped is true, packages are nested under their derived group;
+// keyDisplay controls whether entries show the name, the key, or both.
+func formatPackagesTreeFromState(sp *suprvisor.UnderSupervision, grouped bool, keyDisplay string) string {
 	// Get all items from supervisor
 	items := sp.GetSupervised()
 
@@ -427,6 +2108,8 @@ func formatPackagesTreeFromState(sp *suprvisor.UnderSupervision) string {
 		switch item.Status {
 		case "running":
 			statusStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("42")) // green
+		case "starting":
+			statusStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("11")) // yellow
 		case "stopped":
 			statusStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("243")) // gray
 		case "build_failed":
@@ -435,32 +2118,61 @@ func formatPackagesTreeFromState(sp *suprvisor.UnderSupervision) string {
 			statusStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("243")) // gray
 		}
 
-		treeItems[i] = fmt.Sprintf("%s %s %s",
-			item.Name,
-			hashStyle.Render("#"+key),
+		treeItems[i] = fmt.Sprintf("%s %s",
+			renderNameKey(displayName(item), key, keyDisplay),
 			statusStyle.Render(item.Status),
 		)
 	}
 
 	// Build the tree
 	t := tree.Root("⚡ Nixtea Packages").
-		Child(treeItems...).
 		Enumerator(tree.RoundedEnumerator).
 		EnumeratorStyle(enumeratorStyle).
 		RootStyle(rootStyle).
 		ItemStyle(itmStyle)
 
+	if grouped {
+		// Nest packages under their derived group, keeping group order stable
+		groups := make(map[string][]any)
+		var groupNames []string
+		for i, key := range keys {
+			group := packageGroup(key)
+			if _, seen := groups[group]; !seen {
+				groupNames = append(groupNames, group)
+			}
+			groups[group] = append(groups[group], treeItems[i])
+		}
+		sort.Strings(groupNames)
+
+		for _, group := range groupNames {
+			t.Child(tree.Root(group).Child(groups[group]...))
+		}
+	} else {
+		t.Child(treeItems...)
+	}
+
 	return t.String()
 }
 
+// pkgListJSON is the scripting-friendly shape emitted by 'pkgs -o json'
+type pkgListJSON struct {
+	Name       string `json:"name"`
+	Key        string `json:"key"`
+	Status     string `json:"status"`
+	Pid        int    `json:"pid"`
+	BinaryPath string `json:"binaryPath"`
+}
+
 // pkgsListCmd creates the 'pkgs' command
-func pkgsListCmd(cfg *config.Config, db *db.DB, sp *suprvisor.UnderSupervision) *cobra.Command {
-	return &cobra.Command{
+func pkgsListCmd(cfg *config.Config, db db.Store, sp *suprvisor.UnderSupervision) *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
 		Use:   "pkgs",
 		Short: "List available packages",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// Get current repository URL
-			url, err := db.GetRepoURL()
+			url, err := resolveRepoURL(cmd, db)
 			if err != nil {
 				return fmt.Errorf("failed to get repository URL: %w", err)
 			}
@@ -471,13 +2183,43 @@ func pkgsListCmd(cfg *config.Config, db *db.DB, sp *suprvisor.UnderSupervision)
 
 			// If supervisor has no items, hydrate it first
 			if !sp.HasItems() {
-				if err := sp.HydrateWithTimeout(url, 5*time.Minute); err != nil {
+				if err := sp.HydrateWithTimeout(url, hydrateTimeout(cmd)); err != nil {
 					return fmt.Errorf("failed to hydrate supervisor: %w", err)
 				}
 			}
 
+			// Machine-readable output for scripting, no ANSI
+			if output == "json" {
+				items := sp.GetSupervised()
+
+				keys := make([]string, 0, len(items))
+				for key := range items {
+					keys = append(keys, key)
+				}
+				sort.Strings(keys)
+
+				list := make([]pkgListJSON, 0, len(keys))
+				for _, key := range keys {
+					item := items[key]
+					list = append(list, pkgListJSON{
+						Name:       item.Name,
+						Key:        key,
+						Status:     item.Status,
+						Pid:        item.Pid,
+						BinaryPath: item.BinaryPath,
+					})
+				}
+
+				data, err := json.MarshalIndent(list, "", "  ")
+				if err != nil {
+					return fmt.Errorf("failed to marshal package list: %w", err)
+				}
+				cmd.Println(string(data))
+				return nil
+			}
+
 			// Generate and print the tree
-			tree := formatPackagesTreeFromState(sp)
+			tree := formatPackagesTreeFromState(sp, cfg.GroupPackages, cfg.KeyDisplay)
 			nextSteps := "Next steps: ssh nixtea <pkg> <run/stop/status/logs>\n"
 
 			// Add some spacing around the tree
@@ -491,6 +2233,68 @@ func pkgsListCmd(cfg *config.Config, db *db.DB, sp *suprvisor.UnderSupervision)
 			return nil
 		},
 	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "", "output format (json)")
+
+	return cmd
+}
+
+// renderStatusOverview formats the all-packages status table
+func renderStatusOverview(items map[string]suprvisor.ItemState) string {
+	var out strings.Builder
+
+	headerStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("99")).
+		Bold(true).
+		PaddingBottom(1)
+
+	out.WriteString(headerStyle.Render("Package Status Overview"))
+	out.WriteString("\n\n")
+
+	// Create a tabwriter for aligned output
+	w := tabwriter.NewWriter(&out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "PACKAGE\tSTATUS\tPID\n")
+
+	// Sort keys for consistent output
+	var keys []string
+	for k := range items {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	// Print each package's status
+	for _, key := range keys {
+		item := items[key]
+
+		// Style the status
+		var statusStyle lipgloss.Style
+		switch item.Status {
+		case "running":
+			statusStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
+		case "starting":
+			statusStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("11"))
+		case "stopped":
+			statusStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("203"))
+		default:
+			statusStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("243"))
+		}
+
+		pid := "-"
+		if item.Pid > 0 {
+			pid = fmt.Sprintf("%d", item.Pid)
+			if item.Replicas > 1 {
+				pid = fmt.Sprintf("%d (+%d replicas)", item.Pid, item.Replicas-1)
+			}
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\n",
+			displayName(item),
+			statusStyle.Render(item.Status),
+			pid)
+	}
+	w.Flush()
+
+	return out.String()
 }
 
 // Add this helper function to format package status
@@ -510,17 +2314,93 @@ func formatPackageStatus(name string, state suprvisor.ItemState) string {
 	}
 
 	fmt.Fprintf(w, "Name:\t%s\n", name)
+	if state.Label != "" {
+		fmt.Fprintf(w, "Label:\t%s\n", state.Label)
+	}
 	fmt.Fprintf(w, "Status:\t%s\n", statusStyle.Render(state.Status))
 	if state.Pid > 0 {
 		fmt.Fprintf(w, "PID:\t%d\n", state.Pid)
 	}
+	if state.Replicas > 1 {
+		fmt.Fprintf(w, "Replicas:\t%d\n", state.Replicas)
+	}
 	if state.BinaryPath != "" {
 		fmt.Fprintf(w, "Binary:\t%s\n", state.BinaryPath)
 	}
+	if state.StorePath != "" {
+		fmt.Fprintf(w, "Store Path:\t%s\n", state.StorePath)
+	}
+	if state.BuildError != "" {
+		fmt.Fprintf(w, "Build Error:\t%s\n", state.BuildError)
+	}
 	w.Flush()
 	return status.String()
 }
 
+// packageConfigLoader builds the hook the supervisor uses to load
+// persisted run configuration for the active repository
+func packageConfigLoader(database db.Store) func() (map[string]suprvisor.RunConfig, error) {
+	return func() (map[string]suprvisor.RunConfig, error) {
+		repos, err := database.GetRepos()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get repositories: %w", err)
+		}
+
+		var repoID string
+		for _, repo := range repos {
+			if repo.Active {
+				repoID = repo.ID
+				break
+			}
+		}
+		if repoID == "" {
+			return nil, nil
+		}
+
+		raw, err := database.GetPackageConfigs(repoID)
+		if err != nil {
+			return nil, err
+		}
+
+		configs := make(map[string]suprvisor.RunConfig, len(raw))
+		for key, data := range raw {
+			var cfg suprvisor.RunConfig
+			if err := json.Unmarshal([]byte(data), &cfg); err != nil {
+				return nil, fmt.Errorf("invalid config for %s: %w", key, err)
+			}
+			configs[key] = cfg
+		}
+		return configs, nil
+	}
+}
+
+// exitCodeError carries a specific exit status from a command up to the
+// SSH session so remote callers see the real code
+type exitCodeError struct {
+	code int
+	msg  string
+}
+
+func (e *exitCodeError) Error() string { return e.msg }
+
+// watchInterruptBytes reads a session's stdin one byte at a time and
+// cancels when Ctrl-C (0x03) or Ctrl-D (0x04) arrives — or when the
+// reader errors out, which is how a closed session shows up
+func watchInterruptBytes(r io.Reader, cancel context.CancelFunc) {
+	buf := make([]byte, 1)
+	for {
+		n, err := r.Read(buf)
+		if err != nil {
+			cancel()
+			return
+		}
+		if n > 0 && (buf[0] == 0x03 || buf[0] == 0x04) {
+			cancel()
+			return
+		}
+	}
+}
+
 // CreateMiddleware creates a wish middleware that handles CLI commands
 func CreateMiddleware(cfg *config.Config, sp *suprvisor.UnderSupervision) wish.Middleware {
 	return func(next ssh.Handler) ssh.Handler {
@@ -531,16 +2411,47 @@ func CreateMiddleware(cfg *config.Config, sp *suprvisor.UnderSupervision) wish.M
 				return
 			}
 
-			ctx := context.WithValue(sess.Context(), "session", sess)
+			var ctx context.Context = context.WithValue(sess.Context(), "session", sess)
+
+			// A command plus a PTY (ssh -t nixtea pkgs logs app) means
+			// streaming commands need interactive behavior: Ctrl-C should
+			// detach cleanly, and resize events must be consumed or the
+			// session backs up
+			if _, winCh, hasPty := sess.Pty(); hasPty {
+				if cfg.Banner != "" {
+					fmt.Fprintf(sess, "%s\n\n", cfg.Banner)
+				}
+
+				cctx, cancel := context.WithCancel(ctx)
+				defer cancel()
+				ctx = cctx
+
+				// Nothing reflows mid-stream, but the channel has to be
+				// drained for the client's resizes to be acknowledged
+				go func() {
+					for range winCh {
+					}
+				}()
+
+				// With a PTY an interrupt arrives as a raw byte on stdin,
+				// not a signal. Watching stdin here means commands can't
+				// read it themselves, which only matters for odd cases
+				// like 'repos import -' under a forced TTY.
+				go watchInterruptBytes(sess, cancel)
+			}
 
 			// Initialize database
-			db, err := db.New(cfg)
+			db, err := db.NewStore(cfg)
 			if err != nil {
 				fmt.Fprintf(sess.Stderr(), "Error: %v\n", err)
 				_ = sess.Exit(1)
 				return
 			}
 
+			// Let the supervisor pick up persisted per-package run
+			// configuration on its next hydrate
+			sp.SetConfigLoader(packageConfigLoader(db))
+
 			// Set up root command
 			rootCmd := NewRootCmd(cfg, db, sp)
 			rootCmd.SetContext(ctx)
@@ -553,6 +2464,13 @@ func CreateMiddleware(cfg *config.Config, sp *suprvisor.UnderSupervision) wish.M
 			// Execute command
 			if err := rootCmd.Execute(); err != nil {
 				fmt.Fprintf(sess.Stderr(), "Error: %v\n", err)
+
+				// Foreground runs propagate the child's real exit code
+				var ece *exitCodeError
+				if errors.As(err, &ece) {
+					_ = sess.Exit(ece.code)
+					return
+				}
 				_ = sess.Exit(1)
 				return
 			}
@@ -52,7 +52,7 @@ type repoModel struct {
 	list     list.Model
 	choice   string
 	quitting bool
-	db       *db.DB
+	db       db.Store
 	term     string
 	width    int
 	height   int
@@ -104,7 +104,7 @@ func (m repoModel) View() string {
 	return "\n" + m.list.View()
 }
 
-func handleContextManager(s ssh.Session, db *db.DB) error {
+func handleContextManager(s ssh.Session, db db.Store) error {
 
 	// Get all repositories
 	repos, err := db.GetRepos()
@@ -0,0 +1,50 @@
+// file: internal/cli/limit.go
+
+package cli
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"walross/nixtea/internal/config"
+
+	"github.com/charmbracelet/log"
+	"github.com/charmbracelet/ssh"
+	"github.com/charmbracelet/wish"
+)
+
+// SessionLimitMiddleware bounds the number of concurrent SSH sessions.
+// Connections past the configured limit are rejected with a clear
+// message instead of silently exhausting server resources.
+func SessionLimitMiddleware(cfg *config.Config) wish.Middleware {
+	var active int64
+
+	return func(next ssh.Handler) ssh.Handler {
+		return func(sess ssh.Session) {
+			// MaxSessions is hot-reloadable via SIGHUP, so read it under
+			// the reload lock
+			cfg.Reload.RLock()
+			limit := cfg.MaxSessions
+			cfg.Reload.RUnlock()
+
+			if limit <= 0 {
+				next(sess)
+				return
+			}
+
+			count := atomic.AddInt64(&active, 1)
+			defer atomic.AddInt64(&active, -1)
+
+			if count > int64(limit) {
+				log.Warn("Rejecting connection: session limit reached",
+					"limit", limit,
+					"remote", sess.RemoteAddr())
+				fmt.Fprintf(sess.Stderr(), "Too many concurrent sessions (limit %d), please try again later\n", limit)
+				_ = sess.Exit(1)
+				return
+			}
+
+			next(sess)
+		}
+	}
+}
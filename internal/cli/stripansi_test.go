@@ -0,0 +1,62 @@
+// file: internal/cli/stripansi_test.go
+
+package cli
+
+import "testing"
+
+func TestStripAnsi(t *testing.T) {
+	cases := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			name: "plain line untouched",
+			in:   "listening on :8080",
+			want: "listening on :8080",
+		},
+		{
+			name: "sgr color codes",
+			in:   "\x1b[31merror:\x1b[0m something broke",
+			want: "error: something broke",
+		},
+		{
+			name: "256-color and bold",
+			in:   "\x1b[1m\x1b[38;5;203mwarn\x1b[0m done",
+			want: "warn done",
+		},
+		{
+			name: "cursor movement",
+			in:   "progress\x1b[2K\x1b[1G100%",
+			want: "progress100%",
+		},
+		{
+			name: "osc title with bel terminator",
+			in:   "\x1b]0;my-app\x07ready",
+			want: "ready",
+		},
+		{
+			name: "osc with st terminator",
+			in:   "\x1b]8;;https://example.com\x1b\\link\x1b]8;;\x1b\\",
+			want: "link",
+		},
+		{
+			name: "private mode csi",
+			in:   "\x1b[?25lhidden cursor\x1b[?25h",
+			want: "hidden cursor",
+		},
+		{
+			name: "empty line",
+			in:   "",
+			want: "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := stripAnsi(tc.in); got != tc.want {
+				t.Errorf("stripAnsi(%q) = %q, want %q", tc.in, got, tc.want)
+			}
+		})
+	}
+}
@@ -26,7 +26,21 @@ var (
 			Foreground(lipgloss.Color("99"))
 )
 
-func formatPackagesTree(client *nixapi.Client, repoURL string) (string, error) {
+// renderNameKey renders a package identity per the KEY_DISPLAY setting:
+// just the name, just the styled #key, or (the default) both. Unknown
+// values fall back to both rather than erroring.
+func renderNameKey(name, key, keyDisplay string) string {
+	switch keyDisplay {
+	case "name":
+		return name
+	case "key":
+		return hashStyle.Render("#" + key)
+	default:
+		return fmt.Sprintf("%s %s", name, hashStyle.Render("#"+key))
+	}
+}
+
+func formatPackagesTree(client *nixapi.Client, repoURL string, keyDisplay string) (string, error) {
 	packages, err := client.GetSystemPackages(repoURL)
 	if err != nil {
 		return "", fmt.Errorf("failed to get packages: %w", err)
@@ -43,10 +57,7 @@ func formatPackagesTree(client *nixapi.Client, repoURL string) (string, error) {
 	items := make([]any, len(pkgKeys))
 	for i, key := range pkgKeys {
 		pkg := packages[key]
-		items[i] = fmt.Sprintf("%s %s",
-			pkg.Name,
-			hashStyle.Render("#"+key),
-		)
+		items[i] = renderNameKey(pkg.Name, key, keyDisplay)
 	}
 
 	// Build the tree
@@ -0,0 +1,65 @@
+// file: internal/cli/watch_test.go
+//
+// With a PTY an interrupt arrives as a raw 0x03 on stdin, not a signal;
+// watchInterruptBytes is what turns that byte into a context cancel so
+// streaming commands detach cleanly.
+
+package cli
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+)
+
+// watchCtx wires watchInterruptBytes to a fresh context and hands back
+// the writer feeding its stdin plus the context to observe
+func watchCtx(t *testing.T) (ctx context.Context, stdin *io.PipeWriter) {
+	t.Helper()
+	r, w := io.Pipe()
+	c, cancel := context.WithCancel(context.Background())
+	go watchInterruptBytes(r, cancel)
+	t.Cleanup(func() {
+		cancel()
+		w.Close()
+	})
+	return c, w
+}
+
+// expectCancelled waits for the watcher to cancel the context
+func expectCancelled(t *testing.T, ctx context.Context, why string) {
+	t.Helper()
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatalf("context not cancelled after %s", why)
+	}
+}
+
+func TestWatchInterruptBytesCancelsOnCtrlC(t *testing.T) {
+	ctx, stdin := watchCtx(t)
+
+	// Ordinary typed bytes are ignored
+	stdin.Write([]byte("hello"))
+	select {
+	case <-ctx.Done():
+		t.Fatal("cancelled by ordinary input")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	stdin.Write([]byte{0x03})
+	expectCancelled(t, ctx, "Ctrl-C")
+}
+
+func TestWatchInterruptBytesCancelsOnCtrlD(t *testing.T) {
+	ctx, stdin := watchCtx(t)
+	stdin.Write([]byte{0x04})
+	expectCancelled(t, ctx, "Ctrl-D")
+}
+
+func TestWatchInterruptBytesCancelsOnClosedStdin(t *testing.T) {
+	ctx, stdin := watchCtx(t)
+	stdin.Close()
+	expectCancelled(t, ctx, "stdin close")
+}
@@ -6,9 +6,19 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/log"
 )
 
 type Config struct {
+	// Guards the hot-reloadable fields: the SIGHUP reload goroutine in
+	// main rewrites them while middlewares and commands read concurrently
+	Reload sync.RWMutex
+
 	// Server configuration
 	Host string
 	Port string
@@ -16,9 +26,83 @@ type Config struct {
 	// SSH configuration
 	HostKeyPath string
 
+	// Data directory for persistent state; the database lives here
+	// unless DB_DIR explicitly overrides it
+	DataDir string
+
 	// Database configuration
 	DBDir  string
 	DBName string
+
+	// Storage backend for repository state: "sqlite" (default) or
+	// "memory" for ephemeral deployments
+	StorageBackend string
+
+	// Maximum concurrent SSH sessions (0 disables the limit)
+	MaxSessions int
+
+	// Optional source-address allowlist (CIDRs or bare IPs); when set,
+	// connections from anywhere else are rejected at the door
+	AllowedCIDRs []string
+
+	// Group packages in list/tree views by their key prefix (the part
+	// before the first dash)
+	GroupPackages bool
+
+	// How packages are identified in list/tree views: "both" (default,
+	// name plus #key), "name", or "key". The hash column is pure noise
+	// when every package's name matches its key.
+	KeyDisplay string
+
+	// Read-only TUI mode: run/stop/update keys are disabled, leaving a
+	// safe monitoring dashboard
+	ReadOnly bool
+
+	// Run the TUI in the terminal's alternate screen (the default).
+	// Disabling it keeps the final view in scrollback, which suits
+	// demos, recordings and limited terminals.
+	Altscreen bool
+
+	// Package keys whose child processes get a pseudo-terminal; some
+	// tools only behave well when they detect a TTY
+	TTYPackages []string
+
+	// Logging configuration: level (debug/info/warn/error), format
+	// (text/json), and output ("stderr" or a file path)
+	LogLevel  string
+	LogFormat string
+	LogOutput string
+
+	// How log/status timestamps are rendered: "short" (local HH:MM:SS),
+	// "rfc3339", or "unix"
+	TimestampFormat string
+
+	// Optional welcome banner shown in the TUI header and before
+	// interactive CLI output; BANNER is inline text, BANNER_FILE reads
+	// it from a file
+	Banner string
+
+	// Nix operation timeouts. Evaluation is quick, builds can
+	// legitimately take minutes, so each gets its own bound.
+	EvalTimeout   time.Duration
+	BuildTimeout  time.Duration
+	UpdateTimeout time.Duration
+
+	// Extra binary caches for builds: substituter URLs and the public
+	// keys to trust them by, passed to nix as --option flags
+	Substituters      []string
+	TrustedPublicKeys []string
+
+	// How often the supervisor cross-checks reported process state
+	// against real PID liveness, correcting drift when a process dies
+	// behind its back; 0 disables the reconciler
+	ReconcileInterval time.Duration
+
+	// Build resource bounds passed to nix as --max-jobs/--cores. Hydrate
+	// builds packages one at a time, so these cap total build load end
+	// to end; 0 leaves nix's own defaults in charge.
+	MaxBuildJobs int
+	BuildCores   int
 }
 
 // New creates a new Config instance with values from environment variables
@@ -26,14 +110,25 @@ type Config struct {
 func NewCfg() (*Config, error) {
 	hostKeyPath := os.Getenv("HOST_KEY_PATH")
 	if hostKeyPath == "" {
-		// Default from compile-time variable in main.go
-		hostKeyPath = "/etc/nixtea/ssh/id_ed25519"
+		hostKeyPath = defaultHostKeyPath()
 	}
 
 	// Ensure the SSH directory exists
 	sshDir := filepath.Dir(hostKeyPath)
 	if err := os.MkdirAll(sshDir, 0700); err != nil {
-		return nil, fmt.Errorf("failed to create SSH directory: %w", err)
+		return nil, fmt.Errorf("failed to create SSH directory %s: %w (set HOST_KEY_PATH to a writable location)", sshDir, err)
+	}
+
+	dataDir := getEnvOrDefault("DATA_DIR", defaultDataDir())
+
+	// Inline banner wins; otherwise try the file
+	banner := getEnvOrDefault("BANNER", "")
+	if bannerFile := getEnvOrDefault("BANNER_FILE", ""); banner == "" && bannerFile != "" {
+		if data, err := os.ReadFile(bannerFile); err == nil {
+			banner = strings.TrimRight(string(data), "\n")
+		} else {
+			log.Warn("Failed to read banner file", "path", bannerFile, "error", err)
+		}
 	}
 
 	return &Config{
@@ -44,18 +139,177 @@ func NewCfg() (*Config, error) {
 		// SSH settings
 		HostKeyPath: hostKeyPath,
 
-		// Database settings
-		DBDir:  getEnvOrDefault("DB_DIR", "/tmp"),
-		DBName: getEnvOrDefault("DB_NAME", "nixtea.db"),
+		// Database settings. /tmp is no longer the default — the repo
+		// list is persistent state — but DB_DIR=/tmp still opts in.
+		DataDir: dataDir,
+		DBDir:   getEnvOrDefault("DB_DIR", dataDir),
+		DBName:  getEnvOrDefault("DB_NAME", "nixtea.db"),
+
+		StorageBackend: getEnvOrDefault("STORAGE_BACKEND", "sqlite"),
+
+		// Server limits
+		MaxSessions:  getEnvIntOrDefault("MAX_SESSIONS", 32),
+		AllowedCIDRs: splitList(getEnvOrDefault("ALLOWED_CIDRS", "")),
+
+		// Display settings
+		GroupPackages: getEnvBoolOrDefault("GROUP_PACKAGES", false),
+		KeyDisplay:    getEnvOrDefault("KEY_DISPLAY", "both"),
+		ReadOnly:      getEnvBoolOrDefault("READ_ONLY", false),
+		Altscreen:     getEnvBoolOrDefault("ALTSCREEN", true),
+		TTYPackages:   splitList(getEnvOrDefault("TTY_PACKAGES", "")),
+
+		// Logging settings
+		LogLevel:  getEnvOrDefault("LOG_LEVEL", "info"),
+		LogFormat: getEnvOrDefault("LOG_FORMAT", "text"),
+		LogOutput: getEnvOrDefault("LOG_OUTPUT", "stderr"),
+
+		TimestampFormat: getEnvOrDefault("TIMESTAMP_FORMAT", "short"),
+
+		Banner: banner,
+
+		// Nix operation timeouts
+		EvalTimeout:   getEnvDurationOrDefault("EVAL_TIMEOUT", 30*time.Second),
+		BuildTimeout:  getEnvDurationOrDefault("BUILD_TIMEOUT", 15*time.Minute),
+		UpdateTimeout: getEnvDurationOrDefault("UPDATE_TIMEOUT", 10*time.Minute),
+
+		ReconcileInterval: getEnvDurationOrDefault("RECONCILE_INTERVAL", 30*time.Second),
+
+		// Extra binary caches
+		Substituters:      splitList(getEnvOrDefault("SUBSTITUTERS", "")),
+		TrustedPublicKeys: splitList(getEnvOrDefault("TRUSTED_PUBLIC_KEYS", "")),
+
+		// Build resource bounds
+		MaxBuildJobs: getEnvIntOrDefault("MAX_BUILD_JOBS", 0),
+		BuildCores:   getEnvIntOrDefault("BUILD_CORES", 0),
 	}, nil
 }
 
+// defaultHostKeyPath resolves where the SSH host key lives: the
+// system-wide /etc path when running as root, otherwise the user's XDG
+// config directory so local development works without sudo
+func defaultHostKeyPath() string {
+	if os.Geteuid() == 0 {
+		return "/etc/nixtea/ssh/id_ed25519"
+	}
+
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
+		return filepath.Join(xdg, "nixtea", "ssh", "id_ed25519")
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "/tmp/nixtea/ssh/id_ed25519"
+	}
+	return filepath.Join(home, ".config", "nixtea", "ssh", "id_ed25519")
+}
+
+// defaultDataDir picks a persistent home for nixtea state: the
+// system-wide /var/lib/nixtea when running as root, otherwise the
+// user's XDG data directory
+func defaultDataDir() string {
+	if os.Geteuid() == 0 {
+		return "/var/lib/nixtea"
+	}
+
+	if xdg := os.Getenv("XDG_DATA_HOME"); xdg != "" {
+		return filepath.Join(xdg, "nixtea")
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		// Last resort; better than crashing before config loads
+		return "/tmp/nixtea"
+	}
+	return filepath.Join(home, ".local", "share", "nixtea")
+}
+
+// FormatTimestamp renders a time using the configured TIMESTAMP_FORMAT
+// so timestamps look the same in the TUI and the CLI
+func (c *Config) FormatTimestamp(t time.Time) string {
+	switch c.TimestampFormat {
+	case "rfc3339":
+		return t.Format(time.RFC3339)
+	case "unix":
+		return strconv.FormatInt(t.Unix(), 10)
+	default:
+		return t.Local().Format("15:04:05")
+	}
+}
+
+// splitList turns a comma-separated env value into a cleaned slice,
+// dropping empty entries
+func splitList(value string) []string {
+	var out []string
+	for _, part := range strings.Split(value, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+// Helper function to get a boolean environment variable with default
+// fallback. Resolution is logged at debug level so normal startup stays
+// quiet — stdout belongs to the SSH sessions this binary serves.
+func getEnvBoolOrDefault(key string, defaultValue bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		log.Debug("Using default config value", "key", key, "value", defaultValue)
+		return defaultValue
+	}
+
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Warn("Invalid boolean config value, using default", "key", key, "value", value, "default", defaultValue)
+		return defaultValue
+	}
+
+	log.Debug("Using env config value", "key", key, "value", b)
+	return b
+}
+
+// Helper function to get an integer environment variable with default fallback
+func getEnvIntOrDefault(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		log.Debug("Using default config value", "key", key, "value", defaultValue)
+		return defaultValue
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		log.Warn("Invalid integer config value, using default", "key", key, "value", value, "default", defaultValue)
+		return defaultValue
+	}
+
+	log.Debug("Using env config value", "key", key, "value", n)
+	return n
+}
+
+// Helper function to get a duration environment variable with default fallback
+func getEnvDurationOrDefault(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		log.Debug("Using default config value", "key", key, "value", defaultValue)
+		return defaultValue
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		log.Warn("Invalid duration config value, using default", "key", key, "value", value, "default", defaultValue)
+		return defaultValue
+	}
+
+	log.Debug("Using env config value", "key", key, "value", d)
+	return d
+}
+
 // Helper function to get environment variable with default fallback
 func getEnvOrDefault(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
-		fmt.Printf("Using env value for %s: %s\n", key, value)
+		log.Debug("Using env config value", "key", key, "value", value)
 		return value
 	}
-	fmt.Printf("Using default value for %s: %s\n", key, defaultValue)
+	log.Debug("Using default config value", "key", key, "value", defaultValue)
 	return defaultValue
 }
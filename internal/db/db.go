@@ -32,6 +32,22 @@ func New(cfg *config.Config) (*DB, error) {
 	}
 
 	dbPath := filepath.Join(cfg.DBDir, cfg.DBName)
+
+	// One-time migration from the old /tmp default so existing repo
+	// lists survive the move to a persistent data directory
+	if cfg.DBDir != "/tmp" {
+		oldPath := filepath.Join("/tmp", cfg.DBName)
+		if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+			if _, err := os.Stat(oldPath); err == nil {
+				if err := os.Rename(oldPath, dbPath); err != nil {
+					log.Warn("Failed to migrate database from /tmp", "error", err)
+				} else {
+					log.Info("Migrated database", "from", oldPath, "to", dbPath)
+				}
+			}
+		}
+	}
+
 	db, err := sql.Open("sqlite3", dbPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
@@ -73,9 +89,162 @@ func (m *DB) initSchema() error {
 		return fmt.Errorf("failed to create timestamp trigger: %w", err)
 	}
 
+	// Snapshot of the last hydrate's per-package outputs, used to diff
+	// successive hydrates
+	_, err = m.Exec(`
+		CREATE TABLE IF NOT EXISTS hydrate_snapshot (
+			repo_url TEXT NOT NULL,
+			package_key TEXT NOT NULL,
+			store_path TEXT,
+			status TEXT,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (repo_url, package_key)
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create hydrate_snapshot table: %w", err)
+	}
+
+	// Per-package run configuration (args, env, replicas, restart
+	// policy) stored as JSON so it survives restarts
+	_, err = m.Exec(`
+		CREATE TABLE IF NOT EXISTS package_config (
+			repo_id TEXT NOT NULL,
+			package_key TEXT NOT NULL,
+			config TEXT NOT NULL,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (repo_id, package_key)
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create package_config table: %w", err)
+	}
+
+	return nil
+}
+
+// SnapshotEntry is one package's recorded outcome from a hydrate pass
+type SnapshotEntry struct {
+	StorePath string
+	Status    string
+}
+
+// SaveHydrateSnapshot replaces the stored snapshot for a repository with
+// the given per-package outcomes
+func (m *DB) SaveHydrateSnapshot(repoURL string, entries map[string]SnapshotEntry) error {
+	tx, err := m.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM hydrate_snapshot WHERE repo_url = ?`, repoURL); err != nil {
+		return fmt.Errorf("failed to clear snapshot: %w", err)
+	}
+
+	for key, entry := range entries {
+		_, err := tx.Exec(`
+			INSERT INTO hydrate_snapshot (repo_url, package_key, store_path, status)
+			VALUES (?, ?, ?, ?)
+		`, repoURL, key, entry.StorePath, entry.Status)
+		if err != nil {
+			return fmt.Errorf("failed to save snapshot entry: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit snapshot: %w", err)
+	}
+	return nil
+}
+
+// GetHydrateSnapshot returns the stored snapshot for a repository,
+// keyed by package key. An empty map means no hydrate has been recorded.
+func (m *DB) GetHydrateSnapshot(repoURL string) (map[string]SnapshotEntry, error) {
+	rows, err := m.Query(`
+		SELECT package_key, store_path, status FROM hydrate_snapshot
+		WHERE repo_url = ?
+	`, repoURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get snapshot: %w", err)
+	}
+	defer rows.Close()
+
+	entries := make(map[string]SnapshotEntry)
+	for rows.Next() {
+		var key string
+		var entry SnapshotEntry
+		if err := rows.Scan(&key, &entry.StorePath, &entry.Status); err != nil {
+			return nil, fmt.Errorf("failed to scan snapshot entry: %w", err)
+		}
+		entries[key] = entry
+	}
+	return entries, rows.Err()
+}
+
+// SetPackageConfig upserts the JSON run configuration for a package in
+// a repository
+func (m *DB) SetPackageConfig(repoID, packageKey, config string) error {
+	_, err := m.Exec(`
+		INSERT INTO package_config (repo_id, package_key, config, updated_at)
+		VALUES (?, ?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT (repo_id, package_key)
+		DO UPDATE SET config = excluded.config, updated_at = CURRENT_TIMESTAMP
+	`, repoID, packageKey, config)
+	if err != nil {
+		return fmt.Errorf("failed to save package config: %w", err)
+	}
 	return nil
 }
 
+// GetPackageConfig returns the stored JSON run configuration for a
+// package, or "" if none has been saved
+func (m *DB) GetPackageConfig(repoID, packageKey string) (string, error) {
+	var config string
+	err := m.QueryRow(`
+		SELECT config FROM package_config
+		WHERE repo_id = ? AND package_key = ?
+	`, repoID, packageKey).Scan(&config)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get package config: %w", err)
+	}
+	return config, nil
+}
+
+// GetPackageConfigs returns all stored run configurations for a
+// repository, keyed by package key
+func (m *DB) GetPackageConfigs(repoID string) (map[string]string, error) {
+	rows, err := m.Query(`
+		SELECT package_key, config FROM package_config
+		WHERE repo_id = ?
+	`, repoID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get package configs: %w", err)
+	}
+	defer rows.Close()
+
+	configs := make(map[string]string)
+	for rows.Next() {
+		var key, config string
+		if err := rows.Scan(&key, &config); err != nil {
+			return nil, fmt.Errorf("failed to scan package config: %w", err)
+		}
+		configs[key] = config
+	}
+	return configs, rows.Err()
+}
+
+// SaveRepoURL upserts a repository by URL and makes it active,
+// discarding the returned record. Convenience wrapper over SaveRepo for
+// callers (like the TUI input flow) that don't need the row back.
+func (m *DB) SaveRepoURL(url string) error {
+	_, err := m.SaveRepo(url)
+	return err
+}
+
 // SaveRepo saves a new repository or updates an existing one
 func (m *DB) SaveRepo(url string) (*Repository, error) {
 	tx, err := m.Begin()
@@ -0,0 +1,146 @@
+// file: internal/db/db_test.go
+//
+// Exercises the SQLite backend against a throwaway database file. These
+// focus on the repo upsert semantics the CLI and TUI both lean on:
+// adding is idempotent, and exactly one repo is active at a time.
+
+package db
+
+import (
+	"testing"
+
+	"walross/nixtea/internal/config"
+)
+
+// testDB opens a fresh SQLite database in a per-test temp directory
+func testDB(t *testing.T) *DB {
+	t.Helper()
+	database, err := New(&config.Config{
+		DBDir:  t.TempDir(),
+		DBName: "nixtea-test.db",
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+	return database
+}
+
+func TestSaveRepoReAddIsIdempotent(t *testing.T) {
+	database := testDB(t)
+
+	first, err := database.SaveRepo("github:walross/one")
+	if err != nil {
+		t.Fatalf("SaveRepo: %v", err)
+	}
+	if !first.Active {
+		t.Error("newly saved repo should be active")
+	}
+
+	// Re-adding the same URL must not create a second row — it just
+	// re-activates the existing one
+	again, err := database.SaveRepo("github:walross/one")
+	if err != nil {
+		t.Fatalf("SaveRepo (re-add): %v", err)
+	}
+	if again.ID != first.ID {
+		t.Errorf("re-add created a new ID %q, want original %q", again.ID, first.ID)
+	}
+	if !again.Active {
+		t.Error("re-added repo should be active")
+	}
+
+	repos, err := database.GetRepos()
+	if err != nil {
+		t.Fatalf("GetRepos: %v", err)
+	}
+	if len(repos) != 1 {
+		t.Fatalf("got %d repos after double add, want 1", len(repos))
+	}
+}
+
+func TestSaveRepoURL(t *testing.T) {
+	database := testDB(t)
+
+	// A brand-new URL is inserted and made active
+	if err := database.SaveRepoURL("github:walross/one"); err != nil {
+		t.Fatalf("SaveRepoURL (new): %v", err)
+	}
+	url, err := database.GetRepoURL()
+	if err != nil {
+		t.Fatalf("GetRepoURL: %v", err)
+	}
+	if url != "github:walross/one" {
+		t.Errorf("active URL = %q, want github:walross/one", url)
+	}
+
+	// An existing URL goes through the same upsert as SaveRepo: it is
+	// re-activated, not duplicated
+	if err := database.SaveRepoURL("github:walross/two"); err != nil {
+		t.Fatalf("SaveRepoURL: %v", err)
+	}
+	if err := database.SaveRepoURL("github:walross/one"); err != nil {
+		t.Fatalf("SaveRepoURL (existing): %v", err)
+	}
+
+	url, err = database.GetRepoURL()
+	if err != nil {
+		t.Fatalf("GetRepoURL: %v", err)
+	}
+	if url != "github:walross/one" {
+		t.Errorf("active URL after re-save = %q, want github:walross/one", url)
+	}
+
+	repos, err := database.GetRepos()
+	if err != nil {
+		t.Fatalf("GetRepos: %v", err)
+	}
+	if len(repos) != 2 {
+		t.Errorf("got %d repos, want 2", len(repos))
+	}
+}
+
+func TestSaveRepoSwitchesActive(t *testing.T) {
+	database := testDB(t)
+
+	one, err := database.SaveRepo("github:walross/one")
+	if err != nil {
+		t.Fatalf("SaveRepo: %v", err)
+	}
+	if _, err := database.SaveRepo("github:walross/two"); err != nil {
+		t.Fatalf("SaveRepo: %v", err)
+	}
+
+	// Re-adding the first URL flips the active flag back to it
+	if _, err := database.SaveRepo("github:walross/one"); err != nil {
+		t.Fatalf("SaveRepo (switch back): %v", err)
+	}
+
+	url, err := database.GetRepoURL()
+	if err != nil {
+		t.Fatalf("GetRepoURL: %v", err)
+	}
+	if url != "github:walross/one" {
+		t.Errorf("active URL = %q, want github:walross/one", url)
+	}
+
+	repos, err := database.GetRepos()
+	if err != nil {
+		t.Fatalf("GetRepos: %v", err)
+	}
+	if len(repos) != 2 {
+		t.Errorf("got %d repos, want 2", len(repos))
+	}
+	active := 0
+	for _, repo := range repos {
+		if repo.Active {
+			active++
+			if repo.ID != one.ID {
+				t.Errorf("active repo is %q, want %q", repo.ID, one.ID)
+			}
+		}
+	}
+	if active != 1 {
+		t.Errorf("got %d active repos, want exactly 1", active)
+	}
+}
@@ -0,0 +1,230 @@
+// file: internal/db/store.go
+//
+// the narrow persistence interface for repository management, so the app
+// isn't welded to SQLite: ephemeral deployments (or tests) can use the
+// in-memory backend instead
+
+package db
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"walross/nixtea/internal/config"
+)
+
+// Store covers the persistence operations the rest of the app needs. *DB
+// (SQLite) is the default implementation; MemoryStore keeps everything
+// in process memory.
+type Store interface {
+	SaveRepo(url string) (*Repository, error)
+	SaveRepoURL(url string) error
+	GetRepos() ([]Repository, error)
+	GetRepoByID(id string) (*Repository, error)
+	GetRepoURL() (string, error)
+	DeleteRepo(id string) error
+
+	SetPackageConfig(repoID, packageKey, config string) error
+	GetPackageConfig(repoID, packageKey string) (string, error)
+	GetPackageConfigs(repoID string) (map[string]string, error)
+
+	SaveHydrateSnapshot(repoURL string, entries map[string]SnapshotEntry) error
+	GetHydrateSnapshot(repoURL string) (map[string]SnapshotEntry, error)
+
+	Close() error
+}
+
+// Both backends must keep satisfying the interface
+var (
+	_ Store = (*DB)(nil)
+	_ Store = (*MemoryStore)(nil)
+)
+
+// NewStore returns the storage backend selected by STORAGE_BACKEND:
+// "sqlite" (the default) or "memory"
+func NewStore(cfg *config.Config) (Store, error) {
+	switch cfg.StorageBackend {
+	case "", "sqlite":
+		return New(cfg)
+	case "memory":
+		return NewMemoryStore(), nil
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q (want sqlite or memory)", cfg.StorageBackend)
+	}
+}
+
+// MemoryStore is a Store that lives entirely in process memory; state is
+// lost on restart. Mirrors the SQLite semantics: re-adding an existing
+// URL re-activates it, and exactly one repo is active at a time.
+type MemoryStore struct {
+	mu    sync.Mutex
+	repos map[string]*Repository
+
+	// repoID → packageKey → config JSON
+	pkgConfigs map[string]map[string]string
+
+	// repoURL → packageKey → snapshot entry
+	snapshots map[string]map[string]SnapshotEntry
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		repos:      make(map[string]*Repository),
+		pkgConfigs: make(map[string]map[string]string),
+		snapshots:  make(map[string]map[string]SnapshotEntry),
+	}
+}
+
+func (m *MemoryStore) SaveRepo(url string) (*Repository, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Re-adding an existing URL just makes it active
+	for _, repo := range m.repos {
+		if repo.URL == url {
+			for _, other := range m.repos {
+				other.Active = false
+			}
+			repo.Active = true
+			repo.UpdatedAt = time.Now()
+			copied := *repo
+			return &copied, nil
+		}
+	}
+
+	for _, other := range m.repos {
+		other.Active = false
+	}
+
+	id := fmt.Sprintf("%d-%s", time.Now().UnixNano(), url[:min(8, len(url))])
+	repo := &Repository{
+		ID:        id,
+		URL:       url,
+		Active:    true,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	m.repos[id] = repo
+
+	copied := *repo
+	return &copied, nil
+}
+
+func (m *MemoryStore) GetRepos() ([]Repository, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	repos := make([]Repository, 0, len(m.repos))
+	for _, repo := range m.repos {
+		repos = append(repos, *repo)
+	}
+	return repos, nil
+}
+
+func (m *MemoryStore) GetRepoByID(id string) (*Repository, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	repo, exists := m.repos[id]
+	if !exists {
+		// Mirror the SQLite backend: no rows is nil, nil, not an error
+		return nil, nil
+	}
+	copied := *repo
+	return &copied, nil
+}
+
+func (m *MemoryStore) GetRepoURL() (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, repo := range m.repos {
+		if repo.Active {
+			return repo.URL, nil
+		}
+	}
+	return "", nil
+}
+
+func (m *MemoryStore) DeleteRepo(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.repos[id]; !exists {
+		return fmt.Errorf("repository not found: %s", id)
+	}
+	delete(m.repos, id)
+	return nil
+}
+
+// SaveRepoURL upserts a repository by URL and makes it active,
+// discarding the returned record — same convenience wrapper the SQLite
+// backend offers.
+func (m *MemoryStore) SaveRepoURL(url string) error {
+	_, err := m.SaveRepo(url)
+	return err
+}
+
+func (m *MemoryStore) SetPackageConfig(repoID, packageKey, config string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	configs, ok := m.pkgConfigs[repoID]
+	if !ok {
+		configs = make(map[string]string)
+		m.pkgConfigs[repoID] = configs
+	}
+	configs[packageKey] = config
+	return nil
+}
+
+// GetPackageConfig returns the stored JSON run configuration for a
+// package, or "" if none has been saved
+func (m *MemoryStore) GetPackageConfig(repoID, packageKey string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.pkgConfigs[repoID][packageKey], nil
+}
+
+func (m *MemoryStore) GetPackageConfigs(repoID string) (map[string]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	configs := make(map[string]string, len(m.pkgConfigs[repoID]))
+	for key, config := range m.pkgConfigs[repoID] {
+		configs[key] = config
+	}
+	return configs, nil
+}
+
+// SaveHydrateSnapshot replaces the stored snapshot for a repository with
+// the given per-package outcomes
+func (m *MemoryStore) SaveHydrateSnapshot(repoURL string, entries map[string]SnapshotEntry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	snapshot := make(map[string]SnapshotEntry, len(entries))
+	for key, entry := range entries {
+		snapshot[key] = entry
+	}
+	m.snapshots[repoURL] = snapshot
+	return nil
+}
+
+func (m *MemoryStore) GetHydrateSnapshot(repoURL string) (map[string]SnapshotEntry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entries := make(map[string]SnapshotEntry, len(m.snapshots[repoURL]))
+	for key, entry := range m.snapshots[repoURL] {
+		entries[key] = entry
+	}
+	return entries, nil
+}
+
+// Close is a no-op: there is no connection to release
+func (m *MemoryStore) Close() error {
+	return nil
+}
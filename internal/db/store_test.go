@@ -0,0 +1,229 @@
+// file: internal/db/store_test.go
+//
+// Exercises the in-memory Store backend. MemoryStore is supposed to
+// mirror the SQLite semantics exactly — one active repo at a time,
+// re-adding a URL re-activates it, missing rows are zero values rather
+// than errors — so these tests double as the contract spec for both.
+
+package db
+
+import (
+	"testing"
+
+	"walross/nixtea/internal/config"
+)
+
+func TestNewStoreSelectsBackend(t *testing.T) {
+	store, err := NewStore(&config.Config{StorageBackend: "memory"})
+	if err != nil {
+		t.Fatalf("NewStore(memory): %v", err)
+	}
+	if _, ok := store.(*MemoryStore); !ok {
+		t.Fatalf("NewStore(memory) returned %T, want *MemoryStore", store)
+	}
+
+	if _, err := NewStore(&config.Config{StorageBackend: "bogus"}); err == nil {
+		t.Fatal("NewStore(bogus) succeeded, want error")
+	}
+}
+
+func TestMemoryStoreSaveRepoActivates(t *testing.T) {
+	store := NewMemoryStore()
+
+	first, err := store.SaveRepo("github:walross/one")
+	if err != nil {
+		t.Fatalf("SaveRepo: %v", err)
+	}
+	if !first.Active {
+		t.Error("first repo should be active after save")
+	}
+
+	second, err := store.SaveRepo("github:walross/two")
+	if err != nil {
+		t.Fatalf("SaveRepo: %v", err)
+	}
+	if !second.Active {
+		t.Error("second repo should be active after save")
+	}
+
+	// Saving the second repo must have deactivated the first
+	url, err := store.GetRepoURL()
+	if err != nil {
+		t.Fatalf("GetRepoURL: %v", err)
+	}
+	if url != "github:walross/two" {
+		t.Errorf("active URL = %q, want github:walross/two", url)
+	}
+
+	repos, err := store.GetRepos()
+	if err != nil {
+		t.Fatalf("GetRepos: %v", err)
+	}
+	active := 0
+	for _, repo := range repos {
+		if repo.Active {
+			active++
+		}
+	}
+	if active != 1 {
+		t.Errorf("got %d active repos, want exactly 1", active)
+	}
+}
+
+func TestMemoryStoreReAddIsIdempotent(t *testing.T) {
+	store := NewMemoryStore()
+
+	first, err := store.SaveRepo("github:walross/one")
+	if err != nil {
+		t.Fatalf("SaveRepo: %v", err)
+	}
+	if _, err := store.SaveRepo("github:walross/two"); err != nil {
+		t.Fatalf("SaveRepo: %v", err)
+	}
+
+	// Re-adding the first URL should re-activate the existing record,
+	// not create a duplicate
+	again, err := store.SaveRepo("github:walross/one")
+	if err != nil {
+		t.Fatalf("SaveRepo (re-add): %v", err)
+	}
+	if again.ID != first.ID {
+		t.Errorf("re-add created a new ID %q, want original %q", again.ID, first.ID)
+	}
+	if !again.Active {
+		t.Error("re-added repo should be active")
+	}
+
+	repos, err := store.GetRepos()
+	if err != nil {
+		t.Fatalf("GetRepos: %v", err)
+	}
+	if len(repos) != 2 {
+		t.Errorf("got %d repos after re-add, want 2", len(repos))
+	}
+}
+
+func TestMemoryStoreGetRepoByIDMissing(t *testing.T) {
+	store := NewMemoryStore()
+
+	// The SQLite backend returns nil, nil on no rows — MemoryStore must
+	// match so callers can keep checking repo == nil
+	repo, err := store.GetRepoByID("nope")
+	if err != nil {
+		t.Fatalf("GetRepoByID(missing): %v", err)
+	}
+	if repo != nil {
+		t.Errorf("GetRepoByID(missing) = %+v, want nil", repo)
+	}
+}
+
+func TestMemoryStoreDeleteRepo(t *testing.T) {
+	store := NewMemoryStore()
+
+	repo, err := store.SaveRepo("github:walross/one")
+	if err != nil {
+		t.Fatalf("SaveRepo: %v", err)
+	}
+	if err := store.DeleteRepo(repo.ID); err != nil {
+		t.Fatalf("DeleteRepo: %v", err)
+	}
+	if err := store.DeleteRepo(repo.ID); err == nil {
+		t.Error("deleting a missing repo should error")
+	}
+
+	url, err := store.GetRepoURL()
+	if err != nil {
+		t.Fatalf("GetRepoURL: %v", err)
+	}
+	if url != "" {
+		t.Errorf("active URL after delete = %q, want empty", url)
+	}
+}
+
+func TestMemoryStorePackageConfigs(t *testing.T) {
+	store := NewMemoryStore()
+
+	// Missing config is "", not an error
+	got, err := store.GetPackageConfig("repo-1", "web")
+	if err != nil {
+		t.Fatalf("GetPackageConfig (missing): %v", err)
+	}
+	if got != "" {
+		t.Errorf("missing config = %q, want empty string", got)
+	}
+
+	if err := store.SetPackageConfig("repo-1", "web", `{"label":"Web"}`); err != nil {
+		t.Fatalf("SetPackageConfig: %v", err)
+	}
+	// Second set for the same key is an upsert
+	if err := store.SetPackageConfig("repo-1", "web", `{"label":"Frontend"}`); err != nil {
+		t.Fatalf("SetPackageConfig (upsert): %v", err)
+	}
+	if err := store.SetPackageConfig("repo-1", "api", `{"label":"API"}`); err != nil {
+		t.Fatalf("SetPackageConfig: %v", err)
+	}
+
+	got, err = store.GetPackageConfig("repo-1", "web")
+	if err != nil {
+		t.Fatalf("GetPackageConfig: %v", err)
+	}
+	if got != `{"label":"Frontend"}` {
+		t.Errorf("config = %q, want upserted value", got)
+	}
+
+	configs, err := store.GetPackageConfigs("repo-1")
+	if err != nil {
+		t.Fatalf("GetPackageConfigs: %v", err)
+	}
+	if len(configs) != 2 {
+		t.Errorf("got %d configs, want 2", len(configs))
+	}
+
+	// Configs are scoped per repo
+	configs, err = store.GetPackageConfigs("repo-2")
+	if err != nil {
+		t.Fatalf("GetPackageConfigs (other repo): %v", err)
+	}
+	if len(configs) != 0 {
+		t.Errorf("other repo has %d configs, want 0", len(configs))
+	}
+}
+
+func TestMemoryStoreHydrateSnapshot(t *testing.T) {
+	store := NewMemoryStore()
+
+	url := "github:walross/one"
+	if err := store.SaveHydrateSnapshot(url, map[string]SnapshotEntry{
+		"web": {StorePath: "/nix/store/aaa-web", Status: "built"},
+		"api": {StorePath: "", Status: "failed"},
+	}); err != nil {
+		t.Fatalf("SaveHydrateSnapshot: %v", err)
+	}
+
+	// A second save replaces the whole snapshot, it doesn't merge
+	if err := store.SaveHydrateSnapshot(url, map[string]SnapshotEntry{
+		"web": {StorePath: "/nix/store/bbb-web", Status: "built"},
+	}); err != nil {
+		t.Fatalf("SaveHydrateSnapshot (replace): %v", err)
+	}
+
+	entries, err := store.GetHydrateSnapshot(url)
+	if err != nil {
+		t.Fatalf("GetHydrateSnapshot: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("got %d entries after replace, want 1", len(entries))
+	}
+	if entries["web"].StorePath != "/nix/store/bbb-web" {
+		t.Errorf("web store path = %q, want the replaced value", entries["web"].StorePath)
+	}
+
+	// No hydrate recorded means an empty map, not an error
+	entries, err = store.GetHydrateSnapshot("github:walross/never-hydrated")
+	if err != nil {
+		t.Fatalf("GetHydrateSnapshot (missing): %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("missing snapshot has %d entries, want 0", len(entries))
+	}
+}
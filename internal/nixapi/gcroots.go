@@ -0,0 +1,75 @@
+// file: internal/nixapi/gcroots.go
+//
+// GC-root bookkeeping: a symlink per tracked package keeps its store
+// path alive across `nix-collect-garbage`, and removing the symlink
+// hands the path back to the collector
+
+package nixapi
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// StoreRoot reduces any path inside a store output to the top-level
+// store path, e.g. /nix/store/<hash>-hello/bin/hello -> /nix/store/<hash>-hello
+func StoreRoot(path string) string {
+	const prefix = "/nix/store/"
+	if !strings.HasPrefix(path, prefix) {
+		return path
+	}
+	rest := path[len(prefix):]
+	if idx := strings.Index(rest, "/"); idx != -1 {
+		rest = rest[:idx]
+	}
+	return prefix + rest
+}
+
+// AddGCRoot registers a GC root named after the package key so the
+// store path survives garbage collection while nixtea tracks it
+func AddGCRoot(rootsDir, key, storePath string) error {
+	if err := os.MkdirAll(rootsDir, 0755); err != nil {
+		return fmt.Errorf("failed to create gcroots directory: %w", err)
+	}
+
+	link := filepath.Join(rootsDir, key)
+	// nix-store refuses to overwrite an existing link
+	_ = os.Remove(link)
+
+	cmd := exec.Command("nix-store", "--add-root", link, "-r", StoreRoot(storePath))
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to add gc root: %w\noutput: %s", err, string(output))
+	}
+	return nil
+}
+
+// RemoveGCRoot drops the root for a package key; the underlying store
+// path becomes collectible on the next garbage collection
+func RemoveGCRoot(rootsDir, key string) error {
+	link := filepath.Join(rootsDir, key)
+	if err := os.Remove(link); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove gc root: %w", err)
+	}
+	return nil
+}
+
+// ListGCRoots returns the package keys that currently have a registered
+// root. An absent directory just means no roots yet.
+func ListGCRoots(rootsDir string) ([]string, error) {
+	entries, err := os.ReadDir(rootsDir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read gcroots directory: %w", err)
+	}
+
+	keys := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		keys = append(keys, entry.Name())
+	}
+	return keys, nil
+}
@@ -8,7 +8,9 @@ import (
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/charmbracelet/log"
@@ -30,15 +32,98 @@ type FlakeOutput struct {
 	Packages map[string]map[string]PackageInfo `json:"packages"`
 }
 
+// Default timeouts per operation type. Evaluation (flake show/eval) is
+// quick; builds and flake updates can legitimately take minutes.
+// Guarded by buildOptMu because the SIGHUP reload path can rewrite them
+// while sessions are constructing clients.
+var (
+	defaultEvalTimeout   = 30 * time.Second
+	defaultBuildTimeout  = 15 * time.Minute
+	defaultUpdateTimeout = 10 * time.Minute
+)
+
+// SetDefaultTimeouts overrides the timeouts applied to clients created
+// after the call. Called at startup from config and again on SIGHUP
+// reloads; existing clients keep the timeouts they were built with.
+func SetDefaultTimeouts(eval, build, update time.Duration) {
+	buildOptMu.Lock()
+	defaultEvalTimeout = eval
+	defaultBuildTimeout = build
+	defaultUpdateTimeout = update
+	buildOptMu.Unlock()
+}
+
+// Extra binary caches appended to every build invocation. Empty by
+// default; populated from config at startup. Guarded by buildOptMu
+// because the SIGHUP reload path can rewrite them while builds are
+// rendering their arguments.
+var (
+	buildOptMu             sync.RWMutex
+	extraSubstituters      []string
+	extraTrustedPublicKeys []string
+)
+
+// SetBinaryCaches configures extra substituters and the public keys to
+// trust them by. Builds on machines that can pull from a shared cache
+// are dramatically faster than building from source.
+func SetBinaryCaches(substituters, trustedPublicKeys []string) {
+	buildOptMu.Lock()
+	extraSubstituters = substituters
+	extraTrustedPublicKeys = trustedPublicKeys
+	buildOptMu.Unlock()
+}
+
+// Build resource bounds forwarded to nix; 0 means "let nix decide".
+// Guarded by buildOptMu like the cache settings.
+var (
+	maxBuildJobs int
+	buildCores   int
+)
+
+// SetBuildLimits caps nix's build parallelism (--max-jobs) and
+// per-derivation core usage (--cores) so hydrating a large flake can't
+// overwhelm the machine nixtea runs on
+func SetBuildLimits(maxJobs, cores int) {
+	buildOptMu.Lock()
+	maxBuildJobs = maxJobs
+	buildCores = cores
+	buildOptMu.Unlock()
+}
+
+// cacheOptionArgs renders the configured binary caches and resource
+// bounds as nix flags; nix expects option values space-separated
+func cacheOptionArgs() []string {
+	buildOptMu.RLock()
+	defer buildOptMu.RUnlock()
+
+	var args []string
+	if len(extraSubstituters) > 0 {
+		args = append(args, "--option", "extra-substituters", strings.Join(extraSubstituters, " "))
+	}
+	if len(extraTrustedPublicKeys) > 0 {
+		args = append(args, "--option", "extra-trusted-public-keys", strings.Join(extraTrustedPublicKeys, " "))
+	}
+	if maxBuildJobs > 0 {
+		args = append(args, "--max-jobs", strconv.Itoa(maxBuildJobs))
+	}
+	if buildCores > 0 {
+		args = append(args, "--cores", strconv.Itoa(buildCores))
+	}
+	return args
+}
+
 // Client handles Nix operations
 type Client struct {
-	system  string
-	timeout time.Duration
-	workDir string
+	system        string
+	evalTimeout   time.Duration
+	buildTimeout  time.Duration
+	updateTimeout time.Duration
+	workDir       string
 }
 
-// NewClient creates a new Nix API client
-func NewClient() *Client {
+// NewClient creates a new Nix API client for the given system
+// (e.g. "x86_64-linux"). Use CurrentSystem() for the host's system.
+func NewClient(system string) *Client {
 	// Create temporary work directory
 	workDir, err := os.MkdirTemp("", "nixtea-*")
 	if err != nil {
@@ -47,10 +132,20 @@ func NewClient() *Client {
 		workDir = "/tmp"
 	}
 
+	// Snapshot the defaults under the lock; a SIGHUP reload may be
+	// rewriting them concurrently
+	buildOptMu.RLock()
+	evalTimeout := defaultEvalTimeout
+	buildTimeout := defaultBuildTimeout
+	updateTimeout := defaultUpdateTimeout
+	buildOptMu.RUnlock()
+
 	return &Client{
-		system:  getCurrentSystem(),
-		timeout: 30 * time.Second,
-		workDir: workDir,
+		system:        system,
+		evalTimeout:   evalTimeout,
+		buildTimeout:  buildTimeout,
+		updateTimeout: updateTimeout,
+		workDir:       workDir,
 	}
 }
 
@@ -62,7 +157,9 @@ func (c *Client) Close() error {
 	return nil
 }
 
-func getCurrentSystem() string {
+// CurrentSystem returns the nix system string for the host platform,
+// e.g. "x86_64-linux" or "aarch64-darwin".
+func CurrentSystem() string {
 	var nixArch, nixOS string
 
 	switch runtime.GOARCH {
@@ -88,7 +185,7 @@ func getCurrentSystem() string {
 
 // GetSystemPackages retrieves and filters packages for the current system
 func (c *Client) GetSystemPackages(repoURL string) (map[string]PackageInfo, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	ctx, cancel := context.WithTimeout(context.Background(), c.evalTimeout)
 	defer cancel()
 
 	// Create command with context
@@ -107,7 +204,11 @@ func (c *Client) GetSystemPackages(repoURL string) (map[string]PackageInfo, erro
 		output, err := cmd.CombinedOutput()
 		if err != nil {
 			if ctx.Err() == context.DeadlineExceeded {
-				resultChan <- result{nil, fmt.Errorf("operation timed out after %v", c.timeout)}
+				resultChan <- result{nil, fmt.Errorf("evaluation timed out after %v", c.evalTimeout)}
+				return
+			}
+			if hint := pureEvalHint(err, string(output)); hint != nil {
+				resultChan <- result{nil, hint}
 				return
 			}
 			resultChan <- result{nil, fmt.Errorf("failed to run nix flake show: %w\noutput: %s", err, string(output))}
@@ -143,7 +244,7 @@ func (c *Client) GetSystemPackages(repoURL string) (map[string]PackageInfo, erro
 	select {
 	case <-ctx.Done():
 		if ctx.Err() == context.DeadlineExceeded {
-			return nil, fmt.Errorf("operation timed out after %v", c.timeout)
+			return nil, fmt.Errorf("evaluation timed out after %v", c.evalTimeout)
 		}
 		return nil, ctx.Err()
 	case res := <-resultChan:
@@ -167,9 +268,23 @@ func (c *Client) GetFormattedPackages(repoURL string) ([]PackageDisplay, error)
 	return displayPkgs, nil
 }
 
+// updateMu ensures only one flake update runs at a time across all
+// clients; concurrent updates would collide in the shared work dir and
+// thrash the machine.
+var updateMu sync.Mutex
+
 func (c *Client) UpdateFlake(repoURL string) error {
+	if !updateMu.TryLock() {
+		return fmt.Errorf("an update is already in progress")
+	}
+	defer updateMu.Unlock()
+
 	log.Info("Updating flake", "repo_url", repoURL)
 
+	// The whole update (clone, lock update, check) shares one deadline
+	ctx, cancel := context.WithTimeout(context.Background(), c.updateTimeout)
+	defer cancel()
+
 	// Create a unique working directory for this update
 	updateDir := filepath.Join(c.workDir, fmt.Sprintf("update-%d", time.Now().UnixNano()))
 	if err := os.MkdirAll(updateDir, 0755); err != nil {
@@ -179,30 +294,39 @@ func (c *Client) UpdateFlake(repoURL string) error {
 	fmt.Println("updateDir", updateDir)
 
 	// Step 1: Clone/fetch the latest repository content
-	cmd := exec.Command("nix", "flake", "clone", "--dest", updateDir, repoURL)
+	cmd := exec.CommandContext(ctx, "nix", "flake", "clone", "--dest", updateDir, repoURL)
 
 	if output, err := cmd.CombinedOutput(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("flake update timed out after %v", c.updateTimeout)
+		}
 		return fmt.Errorf("failed to clone flake: %w\noutput: %s", err, string(output))
 	}
 
 	// Step 2: Update the flake's inputs
-	cmd = exec.Command("nix", "flake", "update",
+	cmd = exec.CommandContext(ctx, "nix", "flake", "update",
 		"--commit-lock-file",
 		"--experimental-features", "nix-command flakes",
 		"--option", "warn-dirty", "false",
 		"--flake", updateDir)
 
 	if output, err := cmd.CombinedOutput(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("flake update timed out after %v", c.updateTimeout)
+		}
 		return fmt.Errorf("failed to update flake: %w\noutput: %s", err, string(output))
 	}
 
 	// Step 3: Verify the flake can be evaluated
-	cmd = exec.Command("nix", "flake", "check",
+	cmd = exec.CommandContext(ctx, "nix", "flake", "check",
 		"--no-write-lock-file",
 		"--experimental-features", "nix-command flakes",
 		updateDir)
 
 	if output, err := cmd.CombinedOutput(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("flake update timed out after %v", c.updateTimeout)
+		}
 		return fmt.Errorf("flake check failed: %w\noutput: %s", err, string(output))
 	}
 
@@ -210,6 +334,106 @@ func (c *Client) UpdateFlake(repoURL string) error {
 	return nil
 }
 
+// isPureEvalError recognizes the class of evaluation failures that only
+// occur because nix runs in pure evaluation mode by default
+func isPureEvalError(output string) bool {
+	return strings.Contains(output, "pure evaluation mode") ||
+		strings.Contains(output, "is forbidden in pure eval")
+}
+
+// pureEvalHint wraps a nix failure with a friendly explanation when the
+// underlying cause is pure evaluation mode
+func pureEvalHint(err error, output string) error {
+	if !isPureEvalError(output) {
+		return nil
+	}
+	return fmt.Errorf("this flake requires impure evaluation (e.g. it reads absolute paths or env vars); "+
+		"nix needs the --impure flag, which nixtea does not pass: %w", err)
+}
+
+// Eval evaluates an arbitrary flake attribute with --json and returns
+// the raw JSON value, e.g. Eval(url, "packages.x86_64-linux.web.meta.description").
+// Missing attributes come back as a distinct error so callers can treat
+// them as optional.
+func (c *Client) Eval(repoURL, attrPath string) (json.RawMessage, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.evalTimeout)
+	defer cancel()
+
+	ref := fmt.Sprintf("%s#%s", repoURL, attrPath)
+	cmd := exec.CommandContext(ctx, "nix", "eval", "--json", ref)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("evaluation timed out after %v", c.evalTimeout)
+		}
+		if strings.Contains(string(output), "does not provide attribute") ||
+			strings.Contains(string(output), "missing attribute") {
+			return nil, fmt.Errorf("attribute %s not found in %s", attrPath, repoURL)
+		}
+		return nil, fmt.Errorf("failed to evaluate %s: %w\noutput: %s", ref, err, string(output))
+	}
+
+	raw := json.RawMessage(strings.TrimSpace(string(output)))
+	if !json.Valid(raw) {
+		return nil, fmt.Errorf("nix eval returned invalid JSON for %s", ref)
+	}
+
+	return raw, nil
+}
+
+// GetClosureSize returns the total closure size in bytes for a store path,
+// queried via `nix path-info -S`.
+func (c *Client) GetClosureSize(storePath string) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.evalTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "nix", "path-info", "-S", storePath)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return 0, fmt.Errorf("path-info timed out after %v", c.evalTimeout)
+		}
+		return 0, fmt.Errorf("failed to query path info: %w\noutput: %s", err, string(output))
+	}
+
+	// Output is "<path>\t<closure-size>" — take the last field
+	fields := strings.Fields(strings.TrimSpace(string(output)))
+	if len(fields) < 2 {
+		return 0, fmt.Errorf("unexpected path-info output: %s", string(output))
+	}
+
+	size, err := strconv.ParseInt(fields[len(fields)-1], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse closure size: %w", err)
+	}
+
+	return size, nil
+}
+
+// StorePathExists reports whether a store path is still present and
+// valid in the local nix store
+func (c *Client) StorePathExists(storePath string) bool {
+	if storePath == "" {
+		return false
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.evalTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "nix", "path-info", storePath)
+	return cmd.Run() == nil
+}
+
+// DerivationName extracts the derivation name from a store path,
+// e.g. /nix/store/<hash>-hello-2.12 -> hello-2.12.
+func DerivationName(storePath string) string {
+	base := filepath.Base(storePath)
+	if idx := strings.Index(base, "-"); idx != -1 {
+		return base[idx+1:]
+	}
+	return base
+}
+
 // BuildResult represents the output of building a package
 type BuildResult struct {
 	StorePath  string
@@ -220,11 +444,21 @@ type BuildResult struct {
 func (c *Client) BuildPkg(repoURL, pkgKey string) (*BuildResult, error) {
 	log.Info("Building package", "repo", repoURL, "key", pkgKey)
 
+	ctx, cancel := context.WithTimeout(context.Background(), c.buildTimeout)
+	defer cancel()
+
 	fullPkgURL := fmt.Sprintf("%s#%s", repoURL, pkgKey)
-	buildCmd := exec.Command("nix", "build", "--no-write-lock-file", "--print-out-paths", fullPkgURL)
+	buildArgs := append([]string{"build", "--no-write-lock-file", "--print-out-paths"}, cacheOptionArgs()...)
+	buildCmd := exec.CommandContext(ctx, "nix", append(buildArgs, fullPkgURL)...)
 
 	outBytes, err := buildCmd.CombinedOutput()
 	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("build timed out after %v", c.buildTimeout)
+		}
+		if hint := pureEvalHint(err, string(outBytes)); hint != nil {
+			return nil, hint
+		}
 		return nil, fmt.Errorf("build failed: %w\nOutput: %s", err, string(outBytes))
 	}
 
@@ -294,16 +528,47 @@ func (c *Client) BuildPkg(repoURL, pkgKey string) (*BuildResult, error) {
 	}, nil
 }
 
+// VerifyPkg rebuilds a package from scratch and compares the result
+// against the existing store output (`nix build --rebuild`). An error
+// mentioning differing outputs means the build is not reproducible.
+func (c *Client) VerifyPkg(repoURL, pkgKey string) error {
+	log.Info("Verifying package reproducibility", "repo", repoURL, "key", pkgKey)
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.buildTimeout)
+	defer cancel()
+
+	args := append([]string{"build", "--rebuild", "--no-link", "--no-write-lock-file"}, cacheOptionArgs()...)
+	cmd := exec.CommandContext(ctx, "nix", append(args, fmt.Sprintf("%s#%s", repoURL, pkgKey))...)
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("verification timed out after %v", c.buildTimeout)
+		}
+		if strings.Contains(string(output), "differs from previous") ||
+			strings.Contains(string(output), "may not be deterministic") {
+			return fmt.Errorf("build is not reproducible: %s", strings.TrimSpace(string(output)))
+		}
+		return fmt.Errorf("verification build failed: %w\noutput: %s", err, string(output))
+	}
+	return nil
+}
+
 // BuildPackage handles the building of a package with proper error handling and logging
 func (c *Client) BuildPackage(repoURL, pkgKey string) (*BuildResult, error) {
 	log.Info("Building package", "repo", repoURL, "key", pkgKey)
 
-	cmd := exec.Command("nix", "build",
-		"--print-out-paths", "--no-write-lock-file",
-		fmt.Sprintf("%s#%s", repoURL, pkgKey))
+	ctx, cancel := context.WithTimeout(context.Background(), c.buildTimeout)
+	defer cancel()
+
+	args := append([]string{"build", "--print-out-paths", "--no-write-lock-file"}, cacheOptionArgs()...)
+	cmd := exec.CommandContext(ctx, "nix", append(args, fmt.Sprintf("%s#%s", repoURL, pkgKey))...)
 
 	output, err := cmd.CombinedOutput()
 	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("build timed out after %v", c.buildTimeout)
+		}
 		return nil, fmt.Errorf("build failed: %w\nOutput: %s", err, string(output))
 	}
 
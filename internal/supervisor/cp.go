@@ -9,6 +9,7 @@ package supervisor
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"runtime"
@@ -17,8 +18,10 @@ import (
 	"sync"
 	"syscall"
 	"time"
+	"unicode/utf8"
 
 	"github.com/charmbracelet/log"
+	"github.com/creack/pty"
 )
 
 type NewLogLineMsg struct {
@@ -27,6 +30,16 @@ type NewLogLineMsg struct {
 	Timestamp  time.Time
 }
 
+// ProcessStateChangedMsg is broadcast whenever a process transitions
+// state (started, stopped, crashed), so UIs can update status precisely
+// instead of parsing free-text log lines.
+type ProcessStateChangedMsg struct {
+	ProcessKey string
+	Status     string
+	PID        int
+	ExitCode   int
+}
+
 type ProcessMetadata struct {
 	IsRunning   bool      `json:"is_running"`
 	StartTime   time.Time `json:"start_time"`
@@ -40,16 +53,119 @@ type Process struct {
 	Cmd        *exec.Cmd
 	Done       chan error
 	output     []string
+	subs       []chan string
 	mu         sync.RWMutex
 	pgid       int
 	isRunning  bool
 	startTime  time.Time
 	lastUpdate time.Time
+
+	// CPU sampling state for the /proc-based Linux metrics path; guarded
+	// by its own mutex because getMetadata holds mu for reading
+	cpuMu         sync.Mutex
+	lastCPUTicks  uint64
+	lastCPUSample time.Time
+}
+
+// addLine appends a line to the retained output and forwards it to any
+// log subscribers. Sends are non-blocking so a slow reader can't stall
+// the scanner goroutines.
+func (p *Process) addLine(text string) {
+	p.mu.Lock()
+	p.output = append(p.output, text)
+	subs := make([]chan string, len(p.subs))
+	copy(subs, p.subs)
+	p.mu.Unlock()
+
+	for _, ch := range subs {
+		select {
+		case ch <- text:
+		default:
+		}
+	}
+}
+
+// closeSubs closes all log subscriber channels, signalling end of stream.
+func (p *Process) closeSubs() {
+	p.mu.Lock()
+	subs := p.subs
+	p.subs = nil
+	p.mu.Unlock()
+
+	for _, ch := range subs {
+		close(ch)
+	}
 }
 
 // /////////////////////////////////////////////////////
 // cp methods
 // /////////////////////////////////////////////////////
+// Log capture limits: lines longer than maxLogLineBytes are truncated
+// with a marker, and the scanner buffer is capped so a runaway line
+// can't kill log capture with a "token too long" error.
+const (
+	logScanBufferBytes = 1024 * 1024
+	maxLogLineBytes    = 64 * 1024
+)
+
+// sanitizeLogLine makes captured output safe to retain and broadcast:
+// over-long lines are truncated with a marker and binary data is
+// replaced entirely so it can't corrupt the client terminal
+func sanitizeLogLine(text string) string {
+	if strings.ContainsRune(text, 0) || !utf8.ValidString(text) {
+		return fmt.Sprintf("[binary output omitted (%d bytes)]", len(text))
+	}
+	if len(text) > maxLogLineBytes {
+		return text[:maxLogLineBytes] + " ...[truncated]"
+	}
+	return text
+}
+
+// funnelOutput scans stdout and stderr concurrently but merges both
+// into one channel drained by a single consumer, so the emitted order
+// is one well-defined sequence. With independent per-stream consumers
+// the stored order and the broadcast order could interleave
+// differently. Lines are sanitized and stderr lines carry a "[stderr] "
+// prefix. The wiring is asynchronous; the returned channel closes once
+// both pipes have drained and every line has been emitted.
+func funnelOutput(stdout, stderr io.Reader, emit func(text string)) <-chan struct{} {
+	type scannedLine struct {
+		stream string
+		text   string
+	}
+	lines := make(chan scannedLine, 64)
+
+	var scanners sync.WaitGroup
+	scan := func(r io.Reader, stream string) {
+		defer scanners.Done()
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 64*1024), logScanBufferBytes)
+		for scanner.Scan() {
+			lines <- scannedLine{stream: stream, text: sanitizeLogLine(scanner.Text())}
+		}
+	}
+	scanners.Add(2)
+	go scan(stdout, "stdout")
+	go scan(stderr, "stderr")
+	go func() {
+		scanners.Wait()
+		close(lines)
+	}()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for line := range lines {
+			text := line.text
+			if line.stream == "stderr" {
+				text = fmt.Sprintf("[stderr] %s", text)
+			}
+			emit(text)
+		}
+	}()
+	return done
+}
+
 func (s *Supervisor) StartService(name, key, repoURL string) error {
 	// Check if process already exists and is running
 	s.mu.Lock()
@@ -64,21 +180,27 @@ func (s *Supervisor) StartService(name, key, repoURL string) error {
 	// Create command with proper nix run syntax
 	cmd := exec.Command("nix", "run", "--no-write-lock-file", fmt.Sprintf("%s#%s", repoURL, key))
 
-	// Create a new process group
-	cmd.SysProcAttr = &syscall.SysProcAttr{
-		Setpgid: true,
-	}
+	useTTY := s.wantsTTY(key)
 
-	// Set up stdout pipe
-	stdout, err := cmd.StdoutPipe()
-	if err != nil {
-		return fmt.Errorf("stdout pipe error: %w", err)
-	}
+	var stdout, stderr io.ReadCloser
+	if !useTTY {
+		// Create a new process group
+		cmd.SysProcAttr = &syscall.SysProcAttr{
+			Setpgid: true,
+		}
 
-	// Set up stderr pipe
-	stderr, err := cmd.StderrPipe()
-	if err != nil {
-		return fmt.Errorf("stderr pipe error: %w", err)
+		// Set up stdout pipe
+		var err error
+		stdout, err = cmd.StdoutPipe()
+		if err != nil {
+			return fmt.Errorf("stdout pipe error: %w", err)
+		}
+
+		// Set up stderr pipe
+		stderr, err = cmd.StderrPipe()
+		if err != nil {
+			return fmt.Errorf("stderr pipe error: %w", err)
+		}
 	}
 
 	proc := &Process{
@@ -92,7 +214,20 @@ func (s *Supervisor) StartService(name, key, repoURL string) error {
 	s.processes[key] = proc
 	s.mu.Unlock()
 
-	if err := cmd.Start(); err != nil {
+	var ptmx *os.File
+	if useTTY {
+		// pty.Start allocates the pseudo-terminal, wires it up as the
+		// child's stdio and controlling terminal, and starts the command.
+		// The child becomes a session leader, so its pgid equals its pid.
+		var err error
+		ptmx, err = pty.Start(cmd)
+		if err != nil {
+			s.mu.Lock()
+			delete(s.processes, key)
+			s.mu.Unlock()
+			return fmt.Errorf("failed to start under pty: %w", err)
+		}
+	} else if err := cmd.Start(); err != nil {
 		s.mu.Lock()
 		delete(s.processes, key)
 		s.mu.Unlock()
@@ -100,54 +235,76 @@ func (s *Supervisor) StartService(name, key, repoURL string) error {
 	}
 
 	// Store the process group ID after the process has started
+	var err error
 	proc.pgid, err = syscall.Getpgid(cmd.Process.Pid)
 	if err != nil {
 		return fmt.Errorf("failed to get process group: %w", err)
 	}
 
-	// Handle stdout in a goroutine
-	go func() {
-		scanner := bufio.NewScanner(stdout)
-		for scanner.Scan() {
-			text := scanner.Text()
-			fmt.Println("stdout", text)
+	if useTTY {
+		// A PTY merges stdout and stderr into one stream; relay it into
+		// the same broadcaster. The scanner ends when the child exits and
+		// the terminal returns EIO.
+		go func() {
+			scanner := bufio.NewScanner(ptmx)
+			scanner.Buffer(make([]byte, 64*1024), logScanBufferBytes)
+			for scanner.Scan() {
+				text := sanitizeLogLine(scanner.Text())
+				s.broadcast(NewLogLineMsg{
+					ProcessKey: key,
+					Text:       text,
+					Timestamp:  time.Now(),
+				})
+
+				proc.addLine(text)
+			}
+		}()
+	} else {
+		// Funnel both pipes through one ordered stream; every line gets
+		// its timestamp, retained slot and broadcast in one place, in
+		// one order
+		funnelOutput(stdout, stderr, func(text string) {
 			s.broadcast(NewLogLineMsg{
 				ProcessKey: key,
 				Text:       text,
 				Timestamp:  time.Now(),
 			})
 
-			proc.mu.Lock()
-			proc.output = append(proc.output, text)
-			proc.mu.Unlock()
-		}
-	}()
-
-	// Handle stderr in a goroutine
-	go func() {
-		scanner := bufio.NewScanner(stderr)
-		for scanner.Scan() {
-			text := scanner.Text()
-			fmt.Println("stderr", text)
-			s.broadcast(NewLogLineMsg{
-				ProcessKey: key,
-				Text:       fmt.Sprintf("[stderr] %s", text),
-				Timestamp:  time.Now(),
-			})
-
-			proc.mu.Lock()
-			proc.output = append(proc.output, text)
-			proc.mu.Unlock()
-		}
-	}()
+			// Retain the same rendered text that was broadcast,
+			// [stderr] prefix included, so replayed output matches
+			// what a live session saw (and keeps the stderr
+			// highlighting in the detail view)
+			proc.addLine(text)
+		})
+	}
 
 	// Monitor process completion in a goroutine
 	go func() {
 		err := cmd.Wait()
+		if ptmx != nil {
+			ptmx.Close()
+		}
 		proc.mu.Lock()
 		proc.isRunning = false
 		proc.mu.Unlock()
 
+		// The exit goroutine is the single source of the "stopped"
+		// transition, whether the process exited on its own or was
+		// stopped via StopService
+		exitCode := 0
+		if err != nil {
+			if exitErr, ok := err.(*exec.ExitError); ok {
+				exitCode = exitErr.ExitCode()
+			} else {
+				exitCode = -1
+			}
+		}
+		s.broadcast(ProcessStateChangedMsg{
+			ProcessKey: key,
+			Status:     "stopped",
+			ExitCode:   exitCode,
+		})
+
 		s.broadcast(NewLogLineMsg{
 			ProcessKey: key,
 			Text:       fmt.Sprintf("Process exited: %v", err),
@@ -157,6 +314,9 @@ func (s *Supervisor) StartService(name, key, repoURL string) error {
 		// Send completion status to Done channel
 		proc.Done <- err
 
+		// Signal end of stream to any log subscribers
+		proc.closeSubs()
+
 		// Keep the process in the map but marked as not running
 		// This allows viewing logs after completion
 		log.Info("Process completed", "key", key, "error", err)
@@ -166,6 +326,12 @@ func (s *Supervisor) StartService(name, key, repoURL string) error {
 	log.Info("Added process to map", "key", key, "pid", cmd.Process.Pid)
 	s.logProcessMapState("After starting service")
 
+	s.broadcast(ProcessStateChangedMsg{
+		ProcessKey: key,
+		Status:     "running",
+		PID:        cmd.Process.Pid,
+	})
+
 	return nil
 }
 
@@ -249,9 +415,10 @@ func (s *Supervisor) GetMetadata(key string) (*ProcessMetadata, error) {
 	return &metadata, nil
 }
 
+// ServicePkgLogs returns a channel that first replays the retained output
+// for a process and then follows new lines as they arrive. The channel is
+// closed when the process exits.
 func (s *Supervisor) ServicePkgLogs(key string) (chan string, error) {
-	logChan := make(chan string)
-
 	s.mu.RLock()
 	proc, exists := s.processes[key]
 	s.mu.RUnlock()
@@ -260,25 +427,68 @@ func (s *Supervisor) ServicePkgLogs(key string) (chan string, error) {
 		return nil, fmt.Errorf("no process found for key %s", key)
 	}
 
-	procFile := fmt.Sprintf("/proc/%d/fd/1", proc.Cmd.Process.Pid)
-	file, err := os.Open(procFile)
-	if err != nil {
-		return nil, fmt.Errorf("failed to open process stdout: %w", err)
+	// Snapshot and subscribe under one hold of the lock so no line can
+	// slip through between the backlog copy and the subscription. The
+	// channel is buffered to fit the whole backlog plus headroom for
+	// live lines, so the replay sends below can never block.
+	proc.mu.Lock()
+	logChan := make(chan string, len(proc.output)+100)
+	for _, line := range proc.output {
+		logChan <- line
 	}
+	running := proc.isRunning
+	if running {
+		// Follow new lines from the scanner goroutines
+		proc.subs = append(proc.subs, logChan)
+	}
+	proc.mu.Unlock()
 
-	go func() {
-		defer file.Close()
-		defer close(logChan)
-
-		scanner := bufio.NewScanner(file)
-		for scanner.Scan() {
-			logChan <- scanner.Text()
-		}
-	}()
+	if !running {
+		close(logChan)
+	}
 
 	return logChan, nil
 }
 
+// LastOutputLine returns the most recent retained output line for a
+// process, or "" if there is none. Useful for surfacing the reason a
+// process died right after starting.
+func (s *Supervisor) LastOutputLine(key string) string {
+	s.mu.RLock()
+	proc, exists := s.processes[key]
+	s.mu.RUnlock()
+
+	if !exists || proc == nil {
+		return ""
+	}
+
+	proc.mu.RLock()
+	defer proc.mu.RUnlock()
+	if len(proc.output) == 0 {
+		return ""
+	}
+	return proc.output[len(proc.output)-1]
+}
+
+// RetainedOutput returns a copy of every line captured from a process
+// so far, or nil when the package has never been started. Lets a UI
+// backfill history for processes it didn't start itself.
+func (s *Supervisor) RetainedOutput(key string) []string {
+	s.mu.RLock()
+	proc, exists := s.processes[key]
+	s.mu.RUnlock()
+
+	if !exists || proc == nil {
+		return nil
+	}
+
+	proc.mu.RLock()
+	defer proc.mu.RUnlock()
+	lines := make([]string, len(proc.output))
+	copy(lines, proc.output)
+	return lines
+}
+
 func (s *Supervisor) GetProcess(key string) (*Process, bool) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
@@ -320,29 +530,91 @@ func (p *Process) getMetadata() ProcessMetadata {
 	return metadata
 }
 
+// getLinuxMemoryUsage reads RSS straight from /proc/<pid>/statm instead
+// of forking ps on every refresh
 func (p *Process) getLinuxMemoryUsage() string {
-	cmd := exec.Command("ps", "-o", "rss=", "-p", strconv.Itoa(p.Cmd.Process.Pid))
-	output, err := cmd.Output()
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/statm", p.Cmd.Process.Pid))
 	if err != nil {
 		return "N/A"
 	}
 
-	memKB, err := strconv.ParseFloat(strings.TrimSpace(string(output)), 64)
+	fields := strings.Fields(string(data))
+	if len(fields) < 2 {
+		return "N/A"
+	}
+
+	pages, err := strconv.ParseFloat(fields[1], 64)
 	if err != nil {
 		return "N/A"
 	}
 
-	return fmt.Sprintf("%.1f MB", memKB/1024)
+	return fmt.Sprintf("%.1f MB", pages*float64(os.Getpagesize())/(1024*1024))
 }
 
+// getLinuxCPUUsage computes CPU% from the jiffy delta between two reads
+// of /proc/<pid>/stat. The first sample has no baseline and reports 0.
 func (p *Process) getLinuxCPUUsage() string {
-	cmd := exec.Command("ps", "-o", "%cpu=", "-p", strconv.Itoa(p.Cmd.Process.Pid))
-	output, err := cmd.Output()
+	ticks, err := readProcCPUTicks(p.Cmd.Process.Pid)
 	if err != nil {
 		return "N/A"
 	}
 
-	return strings.TrimSpace(string(output)) + "%"
+	now := time.Now()
+
+	p.cpuMu.Lock()
+	defer p.cpuMu.Unlock()
+
+	if p.lastCPUSample.IsZero() {
+		p.lastCPUTicks = ticks
+		p.lastCPUSample = now
+		return "0.0%"
+	}
+
+	elapsed := now.Sub(p.lastCPUSample).Seconds()
+	delta := ticks - p.lastCPUTicks
+	p.lastCPUTicks = ticks
+	p.lastCPUSample = now
+
+	if elapsed <= 0 {
+		return "N/A"
+	}
+
+	// USER_HZ is 100 on every Linux this will realistically run on
+	const hz = 100.0
+	return fmt.Sprintf("%.1f%%", float64(delta)/hz/elapsed*100)
+}
+
+// readProcCPUTicks returns the combined user+system jiffies for a pid,
+// parsed from /proc/<pid>/stat. The comm field is parenthesised and may
+// contain spaces, so parsing starts after the closing paren.
+func readProcCPUTicks(pid int) (uint64, error) {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return 0, err
+	}
+
+	raw := string(data)
+	idx := strings.LastIndex(raw, ")")
+	if idx < 0 {
+		return 0, fmt.Errorf("malformed stat for pid %d", pid)
+	}
+
+	// After the comm field: fields[0] is the state (overall field 3), so
+	// utime/stime (overall fields 14/15) land at indexes 11 and 12
+	fields := strings.Fields(raw[idx+1:])
+	if len(fields) < 13 {
+		return 0, fmt.Errorf("malformed stat for pid %d", pid)
+	}
+
+	utime, err := strconv.ParseUint(fields[11], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	stime, err := strconv.ParseUint(fields[12], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	return utime + stime, nil
 }
 
 func (p *Process) getDarwinMemoryUsage() string {
@@ -0,0 +1,102 @@
+// file: internal/supervisor/cp_test.go
+
+package supervisor
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+// drainAvailable reads everything currently buffered on ch without
+// blocking on the open channel
+func drainAvailable(ch chan string) []string {
+	var lines []string
+	for {
+		select {
+		case line, ok := <-ch:
+			if !ok {
+				return lines
+			}
+			lines = append(lines, line)
+		default:
+			return lines
+		}
+	}
+}
+
+func TestServicePkgLogsSubscribeMidStream(t *testing.T) {
+	sv := NewSupervisor()
+
+	proc := &Process{isRunning: true}
+	for i := 0; i < 5; i++ {
+		proc.addLine(fmt.Sprintf("backlog %d", i))
+	}
+
+	sv.mu.Lock()
+	sv.processes["svc"] = proc
+	sv.mu.Unlock()
+
+	ch, err := sv.ServicePkgLogs("svc")
+	if err != nil {
+		t.Fatalf("ServicePkgLogs: %v", err)
+	}
+
+	// Lines produced after subscribing must arrive after the backlog —
+	// nothing dropped in between
+	proc.addLine("live 0")
+	proc.addLine("live 1")
+
+	want := []string{"backlog 0", "backlog 1", "backlog 2", "backlog 3", "backlog 4", "live 0", "live 1"}
+	got := drainAvailable(ch)
+	if len(got) != len(want) {
+		t.Fatalf("received %d lines %v, want %d", len(got), got, len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("line %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+
+	// Process exit closes the stream
+	proc.closeSubs()
+	select {
+	case _, ok := <-ch:
+		if ok {
+			t.Error("expected channel to be closed after closeSubs")
+		}
+	case <-time.After(time.Second):
+		t.Error("channel not closed after closeSubs")
+	}
+}
+
+func TestServicePkgLogsStoppedProcessReplaysAndCloses(t *testing.T) {
+	sv := NewSupervisor()
+
+	proc := &Process{isRunning: false}
+	proc.addLine("old line")
+
+	sv.mu.Lock()
+	sv.processes["svc"] = proc
+	sv.mu.Unlock()
+
+	ch, err := sv.ServicePkgLogs("svc")
+	if err != nil {
+		t.Fatalf("ServicePkgLogs: %v", err)
+	}
+
+	line, ok := <-ch
+	if !ok || line != "old line" {
+		t.Fatalf("got (%q, %v), want retained line", line, ok)
+	}
+	if _, ok := <-ch; ok {
+		t.Error("channel for a stopped process should close after replay")
+	}
+}
+
+func TestServicePkgLogsUnknownKey(t *testing.T) {
+	sv := NewSupervisor()
+	if _, err := sv.ServicePkgLogs("nope"); err == nil {
+		t.Error("expected error for unknown key")
+	}
+}
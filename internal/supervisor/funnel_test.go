@@ -0,0 +1,112 @@
+// file: internal/supervisor/funnel_test.go
+//
+// The stdout/stderr funnel is what guarantees retained logs and live
+// broadcasts see the same line sequence. These tests feed it through
+// io.Pipe pairs, standing in for the child process's pipes.
+
+package supervisor
+
+import (
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFunnelOutputInterleavesInArrivalOrder(t *testing.T) {
+	outR, outW := io.Pipe()
+	errR, errW := io.Pipe()
+
+	var (
+		mu      sync.Mutex
+		emitted []string
+	)
+	lineEmitted := make(chan struct{}, 16)
+	done := funnelOutput(outR, errR, func(text string) {
+		mu.Lock()
+		emitted = append(emitted, text)
+		mu.Unlock()
+		lineEmitted <- struct{}{}
+	})
+
+	// Write one line at a time and wait for it to come out the other
+	// end, so the arrival order at the funnel is deterministic
+	write := func(w io.Writer, line string) {
+		t.Helper()
+		if _, err := io.WriteString(w, line+"\n"); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		select {
+		case <-lineEmitted:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("line %q never emitted", line)
+		}
+	}
+
+	write(outW, "starting up")
+	write(errW, "cannot bind :80")
+	write(outW, "retrying on :8080")
+	write(errW, "deprecation warning")
+
+	outW.Close()
+	errW.Close()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("funnel did not drain after both pipes closed")
+	}
+
+	want := []string{
+		"starting up",
+		"[stderr] cannot bind :80",
+		"retrying on :8080",
+		"[stderr] deprecation warning",
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(emitted) != len(want) {
+		t.Fatalf("emitted %d lines %v, want %d", len(emitted), emitted, len(want))
+	}
+	for i := range want {
+		if emitted[i] != want[i] {
+			t.Errorf("line %d = %q, want %q", i, emitted[i], want[i])
+		}
+	}
+}
+
+func TestFunnelOutputSanitizes(t *testing.T) {
+	outR, outW := io.Pipe()
+	errR, errW := io.Pipe()
+	errW.Close()
+
+	var (
+		mu      sync.Mutex
+		emitted []string
+	)
+	done := funnelOutput(outR, errR, func(text string) {
+		mu.Lock()
+		emitted = append(emitted, text)
+		mu.Unlock()
+	})
+
+	go func() {
+		io.WriteString(outW, "binary:\x00\x01\x02\n")
+		outW.Close()
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("funnel did not drain")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(emitted) != 1 {
+		t.Fatalf("emitted %d lines, want 1", len(emitted))
+	}
+	if !strings.Contains(emitted[0], "binary output omitted") {
+		t.Errorf("binary line passed through unsanitized: %q", emitted[0])
+	}
+}
@@ -5,21 +5,133 @@
 
 package supervisor
 
-import tea "github.com/charmbracelet/bubbletea"
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/log"
+)
+
+// programPruneInterval is how often the broadcast list is swept for
+// programs whose sessions died without a clean RemoveProgram
+const programPruneInterval = time.Minute
+
+// safeSend delivers one message to a program, absorbing a panic from a
+// program whose session tore down mid-send so the pump survives. The
+// return value reports whether the send completed.
+func safeSend(p *tea.Program, msg tea.Msg) (ok bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Warn("Dropped broadcast to closed program", "panic", r)
+		}
+	}()
+	p.Send(msg)
+	return true
+}
 
 func (s *Supervisor) AddProgram(p *tea.Program) {
+	s.AddProgramWithDone(p, nil)
+}
+
+// AddProgramWithDone registers a program along with its session's Done
+// channel, so the pruner can evict it once the session ends even if the
+// normal cleanup path never ran
+func (s *Supervisor) AddProgramWithDone(p *tea.Program, done <-chan struct{}) {
+	sub := &subscriber{
+		program: p,
+		msgs:    make(chan tea.Msg, subscriberBuffer),
+		quit:    make(chan struct{}),
+		dead:    make(chan struct{}),
+		done:    done,
+	}
+
 	s.mu.Lock()
-	defer s.mu.Unlock()
-	s.programs = append(s.programs, p)
+	s.subscribers[p] = sub
+	s.mu.Unlock()
+
+	// Pump queued messages into the program so a slow session only
+	// stalls its own queue, never the broadcaster. The quit check wins
+	// over a backlog so a removed program stops receiving promptly.
+	go func() {
+		for {
+			select {
+			case <-sub.quit:
+				return
+			default:
+			}
+
+			select {
+			case msg := <-sub.msgs:
+				if !safeSend(sub.program, msg) {
+					// The program's session tore down uncleanly; flag
+					// it for the pruner and stop pumping
+					close(sub.dead)
+					return
+				}
+			case <-sub.quit:
+				return
+			}
+		}
+	}()
 }
 
 func (s *Supervisor) RemoveProgram(p *tea.Program) {
 	s.mu.Lock()
-	defer s.mu.Unlock()
-	for i, prog := range s.programs {
-		if prog == p {
-			s.programs = append(s.programs[:i], s.programs[i+1:]...)
-			break
+	sub, exists := s.subscribers[p]
+	delete(s.subscribers, p)
+	s.mu.Unlock()
+
+	if exists {
+		close(sub.quit)
+	}
+}
+
+// subscriberDead reports whether a subscriber's session is done or its
+// pump hit a send panic
+func subscriberDead(sub *subscriber) bool {
+	select {
+	case <-sub.dead:
+		return true
+	default:
+	}
+	if sub.done != nil {
+		select {
+		case <-sub.done:
+			return true
+		default:
 		}
 	}
+	return false
+}
+
+// pruneDeadPrograms periodically sweeps the broadcast list for programs
+// that are no longer reachable. RemoveProgram is only called from the
+// session-context goroutine in BubblerMiddleware; if that goroutine is
+// delayed or the session died uncleanly, stale programs would otherwise
+// linger and every broadcast would waste work queueing for them.
+func (s *Supervisor) pruneDeadPrograms(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		s.prunePrograms()
+	}
+}
+
+// prunePrograms does one sweep: collect the unreachable programs under
+// the read lock, then evict them
+func (s *Supervisor) prunePrograms() {
+	s.mu.RLock()
+	var stale []*tea.Program
+	for p, sub := range s.subscribers {
+		if subscriberDead(sub) {
+			stale = append(stale, p)
+		}
+	}
+	s.mu.RUnlock()
+
+	for _, p := range stale {
+		log.Debug("Pruning dead program from broadcast list")
+		s.RemoveProgram(p)
+	}
 }
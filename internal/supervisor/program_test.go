@@ -0,0 +1,68 @@
+// file: internal/supervisor/program_test.go
+//
+// The broadcast path must never block on a session: each program gets a
+// buffered queue and a pump goroutine, and a full queue drops messages
+// for that session only.
+
+package supervisor
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// nopModel is the smallest possible tea.Model for registering programs
+type nopModel struct{}
+
+func (nopModel) Init() tea.Cmd                       { return nil }
+func (nopModel) Update(tea.Msg) (tea.Model, tea.Cmd) { return nopModel{}, nil }
+func (nopModel) View() string                        { return "" }
+
+func TestBroadcastDoesNotBlockOnStalledProgram(t *testing.T) {
+	sv := NewSupervisor()
+
+	// A program that was never started never drains its queue — the
+	// worst kind of stalled session
+	p := tea.NewProgram(nopModel{})
+	sv.AddProgram(p)
+	defer sv.RemoveProgram(p)
+
+	done := make(chan struct{})
+	go func() {
+		// Well past subscriberBuffer, so the queue is guaranteed full
+		// and Broadcast has to take the drop path
+		for i := 0; i < subscriberBuffer*5; i++ {
+			sv.Broadcast(NewLogLineMsg{Text: "line", Timestamp: time.Now()})
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Broadcast blocked behind a stalled program")
+	}
+}
+
+func TestRemoveProgramStopsDelivery(t *testing.T) {
+	sv := NewSupervisor()
+
+	p := tea.NewProgram(nopModel{})
+	sv.AddProgram(p)
+	sv.RemoveProgram(p)
+
+	sv.mu.RLock()
+	_, exists := sv.subscribers[p]
+	sv.mu.RUnlock()
+	if exists {
+		t.Error("program still registered after RemoveProgram")
+	}
+
+	// Broadcasting to an empty list is a no-op, not a panic
+	sv.Broadcast(NewLogLineMsg{Text: "line", Timestamp: time.Now()})
+
+	// Removing twice is harmless
+	sv.RemoveProgram(p)
+}
@@ -0,0 +1,57 @@
+// file: internal/supervisor/prune_test.go
+//
+// The pruner is the safety net for sessions that die without a clean
+// RemoveProgram: once their Done channel closes they must fall off the
+// broadcast list on the next sweep.
+
+package supervisor
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestPruneProgramsEvictsDeadSessions(t *testing.T) {
+	sv := NewSupervisor()
+
+	// One live session, one whose session context is already done
+	live := tea.NewProgram(nopModel{})
+	liveDone := make(chan struct{})
+	sv.AddProgramWithDone(live, liveDone)
+
+	dead := tea.NewProgram(nopModel{})
+	deadDone := make(chan struct{})
+	sv.AddProgramWithDone(dead, deadDone)
+	close(deadDone) // session died; RemoveProgram never ran
+
+	sv.prunePrograms()
+
+	sv.mu.RLock()
+	_, liveExists := sv.subscribers[live]
+	_, deadExists := sv.subscribers[dead]
+	sv.mu.RUnlock()
+
+	if deadExists {
+		t.Error("dead program survived the prune sweep")
+	}
+	if !liveExists {
+		t.Error("live program was pruned")
+	}
+
+	// A program registered without a done channel is never considered
+	// dead by the session check
+	bare := tea.NewProgram(nopModel{})
+	sv.AddProgram(bare)
+	sv.prunePrograms()
+
+	sv.mu.RLock()
+	_, bareExists := sv.subscribers[bare]
+	sv.mu.RUnlock()
+	if !bareExists {
+		t.Error("program without a done channel was pruned")
+	}
+
+	sv.RemoveProgram(live)
+	sv.RemoveProgram(bare)
+}
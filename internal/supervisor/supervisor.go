@@ -6,41 +6,84 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 )
 
+// subscriberBuffer is how many pending messages a session can lag behind
+// before we start dropping messages for it
+const subscriberBuffer = 100
+
+// subscriber pairs a registered program with its delivery queue. A pump
+// goroutine drains the queue so a stalled session never blocks broadcast.
+type subscriber struct {
+	program *tea.Program
+	msgs    chan tea.Msg
+	quit    chan struct{}
+
+	// dead is closed by the pump when a send panics, flagging the
+	// program for the pruner; done is the session's context Done channel
+	// (nil when the caller didn't supply one)
+	dead chan struct{}
+	done <-chan struct{}
+}
+
 type Supervisor struct {
-	processes map[string]*Process
-	programs  []*tea.Program
-	mu        sync.RWMutex
+	processes   map[string]*Process
+	subscribers map[*tea.Program]*subscriber
+	mu          sync.RWMutex
+
+	// Packages that should run under a pseudo-terminal; many tools only
+	// emit color/progress output when they detect a TTY
+	ttyPkgs map[string]bool
 }
 
 func NewSupervisor() *Supervisor {
-	return &Supervisor{
-		processes: make(map[string]*Process),
-		programs:  make([]*tea.Program, 0),
+	s := &Supervisor{
+		processes:   make(map[string]*Process),
+		subscribers: make(map[*tea.Program]*subscriber),
+		ttyPkgs:     make(map[string]bool),
 	}
+
+	// Safety net: evict programs whose sessions died uncleanly so every
+	// broadcast doesn't keep queueing for them
+	go s.pruneDeadPrograms(programPruneInterval)
+
+	return s
+}
+
+// SetTTY marks a package as wanting a pseudo-terminal for its child
+// process
+func (s *Supervisor) SetTTY(key string, tty bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ttyPkgs[key] = tty
 }
 
+// wantsTTY reports whether a package was configured to run under a PTY
+func (s *Supervisor) wantsTTY(key string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.ttyPkgs[key]
+}
+
+// Broadcast enqueues a message for every registered program without
+// blocking: each subscriber has a buffered queue drained by its own pump
+// goroutine, and messages are dropped for subscribers whose queue is full.
 func (s *Supervisor) Broadcast(msg tea.Msg) {
 	s.mu.RLock()
-	programs := make([]*tea.Program, len(s.programs))
-	copy(programs, s.programs)
+	subs := make([]*subscriber, 0, len(s.subscribers))
+	for _, sub := range s.subscribers {
+		subs = append(subs, sub)
+	}
 	s.mu.RUnlock()
 
-	for _, p := range programs {
-		if p != nil {
-			p.Send(msg)
+	for _, sub := range subs {
+		select {
+		case sub.msgs <- msg:
+		default:
+			// Queue full — this session is stalled; drop rather than
+			// back up log ingestion for everyone else
 		}
 	}
 }
 
 func (s *Supervisor) broadcast(msg tea.Msg) {
-	s.mu.RLock()
-	programs := make([]*tea.Program, len(s.programs))
-	copy(programs, s.programs)
-	s.mu.RUnlock()
-
-	for _, p := range programs {
-		if p != nil {
-			p.Send(msg)
-		}
-	}
+	s.Broadcast(msg)
 }
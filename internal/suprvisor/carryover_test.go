@@ -0,0 +1,76 @@
+// file: internal/suprvisor/carryover_test.go
+//
+// A hydrate pass rebuilds the items map from the flake's current
+// package set — but entries that already exist must be carried forward,
+// not recreated, so retained logs, lifecycle history and live process
+// state survive a rebuild.
+
+package suprvisor
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCarryOverRunnableKeepsExistingEntry(t *testing.T) {
+	existing := &Runnable{
+		Name:       "Old Name",
+		BinaryPath: "/nix/store/aaa/bin/web",
+		StorePath:  "/nix/store/aaa",
+		RepoURL:    "github:walross/old",
+		Status:     "running",
+		PID:        4242,
+		logRecords: []RetainedLine{
+			{Time: time.Now(), Stream: "stdout", Text: "still here"},
+		},
+		history: []Event{
+			{Type: "started", Key: "web"},
+		},
+	}
+	previous := map[string]*Runnable{"web": existing}
+
+	runnable, existed := carryOverRunnable(previous, "web", "New Name", "github:walross/new")
+	if !existed {
+		t.Fatal("existing entry reported as new")
+	}
+	if runnable != existing {
+		t.Fatal("carry-over returned a different Runnable — retained state lost")
+	}
+
+	// The refreshed metadata
+	if runnable.Name != "New Name" {
+		t.Errorf("Name = %q, want refreshed name", runnable.Name)
+	}
+	if runnable.RepoURL != "github:walross/new" {
+		t.Errorf("RepoURL = %q, want the new repo", runnable.RepoURL)
+	}
+
+	// Everything else survives untouched
+	if runnable.Status != "running" || runnable.PID != 4242 {
+		t.Errorf("process state changed: status=%q pid=%d", runnable.Status, runnable.PID)
+	}
+	if len(runnable.logRecords) != 1 || runnable.logRecords[0].Text != "still here" {
+		t.Error("retained logs did not survive the carry-over")
+	}
+	if len(runnable.history) != 1 {
+		t.Error("lifecycle history did not survive the carry-over")
+	}
+	if runnable.StorePath != "/nix/store/aaa" {
+		t.Error("store path should be left for the skip-rebuild check")
+	}
+}
+
+func TestCarryOverRunnableCreatesFreshEntry(t *testing.T) {
+	previous := map[string]*Runnable{"web": {Name: "Web"}}
+
+	runnable, existed := carryOverRunnable(previous, "api", "API", "github:walross/new")
+	if existed {
+		t.Fatal("new key reported as existing")
+	}
+	if runnable.Name != "API" || runnable.RepoURL != "github:walross/new" {
+		t.Errorf("fresh entry = %+v, want name and repo set", runnable)
+	}
+	if runnable.Status != "stopped" || runnable.PID != 0 {
+		t.Errorf("fresh entry starts as status=%q pid=%d, want stopped/0", runnable.Status, runnable.PID)
+	}
+}
@@ -0,0 +1,111 @@
+// file: internal/suprvisor/deps.go
+//
+// startup ordering between packages: a package can declare that it must
+// start after others (a DB before the app)
+
+package suprvisor
+
+import (
+	"fmt"
+	"sort"
+)
+
+// SetDependencies records which packages must be running before key
+// starts. It rejects unknown keys and orderings that would introduce a
+// cycle.
+func (s *UnderSupervision) SetDependencies(key string, after []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.items[key]; !exists {
+		return fmt.Errorf("package %s not found", key)
+	}
+	for _, dep := range after {
+		if _, exists := s.items[dep]; !exists {
+			return fmt.Errorf("dependency %s not found", dep)
+		}
+		if dep == key {
+			return fmt.Errorf("package %s cannot depend on itself", key)
+		}
+	}
+
+	if s.deps == nil {
+		s.deps = make(map[string][]string)
+	}
+
+	// Trial the new edge set before committing so a cycle never lands
+	previous := s.deps[key]
+	s.deps[key] = after
+	if _, err := s.startOrderLocked(); err != nil {
+		s.deps[key] = previous
+		return err
+	}
+
+	return nil
+}
+
+// Dependencies returns the declared dependencies for a package
+func (s *UnderSupervision) Dependencies(key string) []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	deps := make([]string, len(s.deps[key]))
+	copy(deps, s.deps[key])
+	return deps
+}
+
+// StartOrder returns every known package key sorted so that dependencies
+// come before their dependents. Returns an error if the declared
+// dependencies contain a cycle.
+func (s *UnderSupervision) StartOrder() ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.startOrderLocked()
+}
+
+// startOrderLocked is the DFS-based topological sort behind StartOrder.
+// Callers must hold s.mu.
+func (s *UnderSupervision) startOrderLocked() ([]string, error) {
+	keys := make([]string, 0, len(s.items))
+	for key := range s.items {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	const (
+		unvisited = 0
+		visiting  = 1
+		done      = 2
+	)
+
+	state := make(map[string]int, len(keys))
+	order := make([]string, 0, len(keys))
+
+	var visit func(key string) error
+	visit = func(key string) error {
+		switch state[key] {
+		case done:
+			return nil
+		case visiting:
+			return fmt.Errorf("dependency cycle detected involving %s", key)
+		}
+
+		state[key] = visiting
+		for _, dep := range s.deps[key] {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		state[key] = done
+		order = append(order, key)
+		return nil
+	}
+
+	for _, key := range keys {
+		if err := visit(key); err != nil {
+			return nil, err
+		}
+	}
+
+	return order, nil
+}
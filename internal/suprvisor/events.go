@@ -0,0 +1,73 @@
+// file: internal/suprvisor/events.go
+//
+// a small event bus over supervisor state changes so external tooling
+// can react without polling status
+
+package suprvisor
+
+import (
+	"sync"
+	"time"
+)
+
+// Event describes one supervisor state change in a JSON-friendly shape
+type Event struct {
+	Type      string    `json:"type"`
+	Key       string    `json:"key,omitempty"`
+	Name      string    `json:"name,omitempty"`
+	PID       int       `json:"pid,omitempty"`
+	Detail    string    `json:"detail,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// eventBus fans supervisor events out to subscribers. Sends never block:
+// a subscriber that stops draining its channel just misses events.
+type eventBus struct {
+	mu   sync.Mutex
+	subs map[chan Event]struct{}
+}
+
+func newEventBus() *eventBus {
+	return &eventBus{
+		subs: make(map[chan Event]struct{}),
+	}
+}
+
+func (b *eventBus) emit(evt Event) {
+	if evt.Timestamp.IsZero() {
+		evt.Timestamp = time.Now()
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.subs {
+		select {
+		case ch <- evt:
+		default:
+		}
+	}
+}
+
+// SubscribeEvents registers an event listener. The returned cancel
+// function must be called when the caller is done; it closes the channel.
+func (s *UnderSupervision) SubscribeEvents() (chan Event, func()) {
+	ch := make(chan Event, 100)
+
+	s.events.mu.Lock()
+	s.events.subs[ch] = struct{}{}
+	s.events.mu.Unlock()
+
+	cancel := func() {
+		s.events.mu.Lock()
+		_, exists := s.events.subs[ch]
+		delete(s.events.subs, ch)
+		s.events.mu.Unlock()
+
+		if exists {
+			close(ch)
+		}
+	}
+
+	return ch, cancel
+}
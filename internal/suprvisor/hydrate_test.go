@@ -0,0 +1,85 @@
+// file: internal/suprvisor/hydrate_test.go
+//
+// Covers the hydrate single-flight slot: a burst of concurrent hydrate
+// requests must coalesce onto one in-progress pass instead of building
+// the same flake in parallel.
+
+package suprvisor
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBeginHydrateCoalescesConcurrentCallers(t *testing.T) {
+	s := NewSupervisor()
+
+	const callers = 16
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		winners int
+		waiters []chan struct{}
+	)
+
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			done, started := s.beginHydrate()
+			mu.Lock()
+			defer mu.Unlock()
+			if started {
+				winners++
+			} else {
+				waiters = append(waiters, done)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if winners != 1 {
+		t.Fatalf("%d callers won the hydrate slot, want exactly 1", winners)
+	}
+	if len(waiters) != callers-1 {
+		t.Fatalf("%d callers got a wait channel, want %d", len(waiters), callers-1)
+	}
+	if !s.IsHydrating() {
+		t.Error("IsHydrating = false while the slot is held")
+	}
+
+	// Everyone waits on the SAME in-flight pass
+	for i := 1; i < len(waiters); i++ {
+		if waiters[i] != waiters[0] {
+			t.Fatal("waiters received different done channels")
+		}
+	}
+
+	// Finishing the pass wakes all waiters and records the outcome
+	hydrateErr := errors.New("build failed")
+	s.endHydrate(hydrateErr)
+
+	select {
+	case <-waiters[0]:
+	case <-time.After(time.Second):
+		t.Fatal("done channel not closed by endHydrate")
+	}
+	if s.IsHydrating() {
+		t.Error("IsHydrating = true after endHydrate")
+	}
+	if !errors.Is(s.hydrateErr, hydrateErr) {
+		t.Errorf("hydrateErr = %v, want the recorded error", s.hydrateErr)
+	}
+
+	// The slot is free again for the next pass
+	done, started := s.beginHydrate()
+	if !started {
+		t.Fatal("beginHydrate after endHydrate did not start a new pass")
+	}
+	if done == waiters[0] {
+		t.Error("new pass reused the previous done channel")
+	}
+	s.endHydrate(nil)
+}
@@ -0,0 +1,82 @@
+package suprvisor
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// UpdateJob records one detached hydrate pass ('ctx update --detach').
+// Finished jobs stay in the registry so they can still be polled after
+// completion.
+type UpdateJob struct {
+	ID         string
+	RepoURL    string
+	Status     string // "running", "succeeded" or "failed"
+	Error      string
+	StartedAt  time.Time
+	FinishedAt time.Time
+}
+
+// StartDetachedHydrate kicks off a hydrate pass in the background and
+// returns a job id the caller can poll. The hydrate itself still goes
+// through the single-flight slot, so a detached update racing a
+// foreground one just coalesces onto it.
+func (s *UnderSupervision) StartDetachedHydrate(repoURL string, timeout time.Duration) string {
+	s.jobsMu.Lock()
+	if s.jobs == nil {
+		s.jobs = make(map[string]*UpdateJob)
+	}
+	s.jobSeq++
+	job := &UpdateJob{
+		ID:        fmt.Sprintf("update-%d", s.jobSeq),
+		RepoURL:   repoURL,
+		Status:    "running",
+		StartedAt: time.Now(),
+	}
+	s.jobs[job.ID] = job
+	s.jobsMu.Unlock()
+
+	go func() {
+		err := s.HydrateWithTimeout(repoURL, timeout)
+
+		s.jobsMu.Lock()
+		job.FinishedAt = time.Now()
+		if err != nil {
+			job.Status = "failed"
+			job.Error = err.Error()
+		} else {
+			job.Status = "succeeded"
+		}
+		s.jobsMu.Unlock()
+	}()
+
+	return job.ID
+}
+
+// UpdateJobStatus returns a snapshot of one detached update job
+func (s *UnderSupervision) UpdateJobStatus(id string) (UpdateJob, bool) {
+	s.jobsMu.Lock()
+	defer s.jobsMu.Unlock()
+
+	job, ok := s.jobs[id]
+	if !ok {
+		return UpdateJob{}, false
+	}
+	return *job, true
+}
+
+// UpdateJobs returns snapshots of all detached update jobs, newest first
+func (s *UnderSupervision) UpdateJobs() []UpdateJob {
+	s.jobsMu.Lock()
+	defer s.jobsMu.Unlock()
+
+	jobs := make([]UpdateJob, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		jobs = append(jobs, *job)
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].StartedAt.After(jobs[j].StartedAt)
+	})
+	return jobs
+}
@@ -0,0 +1,188 @@
+// file: internal/suprvisor/metrics.go
+//
+// resource sampling for 'pkgs top' and 'pkgs inspect'. On Linux the
+// samples come straight from /proc/<pid>/stat and /proc/<pid>/statm, so
+// a refresh tick doesn't fork a ps per tracked process; other platforms
+// fall back to ps.
+
+package suprvisor
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ProcessMetrics is a point-in-time resource sample for a running package
+type ProcessMetrics struct {
+	CPUPercent float64
+	MemoryKB   float64
+}
+
+// cpuSample is the jiffy baseline from the previous read of a pid's
+// /proc stat, used to turn cumulative ticks into a rate
+type cpuSample struct {
+	ticks uint64
+	at    time.Time
+}
+
+// Per-pid CPU baselines for the /proc sampling path. Package-level
+// because samples are keyed by pid, not by which supervisor asked.
+// Entries are dropped when the exit monitor reaps the process, so a
+// crash-looping service doesn't grow the map forever.
+var (
+	cpuSampleMu sync.Mutex
+	cpuSamples  = make(map[int]cpuSample)
+)
+
+// dropCPUSample forgets the baseline for a reaped pid. Called from the
+// exit monitor; also keeps a recycled pid from inheriting a dead
+// process's jiffy count.
+func dropCPUSample(pid int) {
+	cpuSampleMu.Lock()
+	delete(cpuSamples, pid)
+	cpuSampleMu.Unlock()
+}
+
+// GetMetrics samples CPU and memory usage for a running package.
+// Returns an error if the package is not running.
+func (s *UnderSupervision) GetMetrics(key string) (*ProcessMetrics, error) {
+	s.mu.RLock()
+	runnable, exists := s.items[key]
+	var pid int
+	if exists {
+		pid = runnable.PID
+	}
+	s.mu.RUnlock()
+
+	if !exists {
+		return nil, fmt.Errorf("package %s not found", key)
+	}
+	if pid == 0 {
+		return nil, fmt.Errorf("package %s is not running", key)
+	}
+
+	if runtime.GOOS == "linux" {
+		return procMetrics(pid)
+	}
+	return psMetrics(pid)
+}
+
+// procMetrics samples a pid from /proc. The first CPU sample has no
+// baseline and reports 0; subsequent samples report the rate since the
+// previous call.
+func procMetrics(pid int) (*ProcessMetrics, error) {
+	statm, err := os.ReadFile(fmt.Sprintf("/proc/%d/statm", pid))
+	if err != nil {
+		// The pid vanished between the status check and the sample;
+		// adopted processes never pass through the exit monitor, so
+		// clean up the baseline here too
+		dropCPUSample(pid)
+		return nil, fmt.Errorf("failed to sample process %d: %w", pid, err)
+	}
+	memKB, err := parseStatmRSSKB(statm, os.Getpagesize())
+	if err != nil {
+		return nil, fmt.Errorf("failed to sample process %d: %w", pid, err)
+	}
+
+	stat, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return nil, fmt.Errorf("failed to sample process %d: %w", pid, err)
+	}
+	ticks, err := parseStatCPUTicks(stat)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sample process %d: %w", pid, err)
+	}
+
+	return &ProcessMetrics{
+		CPUPercent: cpuPercent(pid, ticks, time.Now()),
+		MemoryKB:   memKB,
+	}, nil
+}
+
+// cpuPercent turns a cumulative jiffy count into a usage percentage
+// against the previous sample for the same pid, updating the baseline
+func cpuPercent(pid int, ticks uint64, now time.Time) float64 {
+	cpuSampleMu.Lock()
+	defer cpuSampleMu.Unlock()
+
+	prev, ok := cpuSamples[pid]
+	cpuSamples[pid] = cpuSample{ticks: ticks, at: now}
+
+	if !ok || ticks < prev.ticks {
+		return 0
+	}
+	elapsed := now.Sub(prev.at).Seconds()
+	if elapsed <= 0 {
+		return 0
+	}
+
+	// USER_HZ is 100 on every Linux this will realistically run on
+	const hz = 100.0
+	return float64(ticks-prev.ticks) / hz / elapsed * 100
+}
+
+// parseStatmRSSKB returns the resident set size in KB from the contents
+// of /proc/<pid>/statm (field 2, counted in pages)
+func parseStatmRSSKB(data []byte, pageSize int) (float64, error) {
+	fields := strings.Fields(string(data))
+	if len(fields) < 2 {
+		return 0, fmt.Errorf("malformed statm: %q", string(data))
+	}
+	pages, err := strconv.ParseFloat(fields[1], 64)
+	if err != nil {
+		return 0, fmt.Errorf("malformed statm rss field: %w", err)
+	}
+	return pages * float64(pageSize) / 1024, nil
+}
+
+// parseStatCPUTicks returns the combined user+system jiffies from the
+// contents of /proc/<pid>/stat. The comm field is parenthesised and may
+// contain spaces, so parsing starts after the closing paren.
+func parseStatCPUTicks(data []byte) (uint64, error) {
+	raw := string(data)
+	idx := strings.LastIndex(raw, ")")
+	if idx < 0 {
+		return 0, fmt.Errorf("malformed stat: no comm field")
+	}
+
+	// After the comm field: fields[0] is the state (overall field 3), so
+	// utime/stime (overall fields 14/15) land at indexes 11 and 12
+	fields := strings.Fields(raw[idx+1:])
+	if len(fields) < 13 {
+		return 0, fmt.Errorf("malformed stat: %d fields after comm", len(fields))
+	}
+
+	utime, err := strconv.ParseUint(fields[11], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("malformed stat utime field: %w", err)
+	}
+	stime, err := strconv.ParseUint(fields[12], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("malformed stat stime field: %w", err)
+	}
+	return utime + stime, nil
+}
+
+// psMetrics samples a pid by forking ps — the non-Linux fallback
+func psMetrics(pid int) (*ProcessMetrics, error) {
+	out, err := exec.Command("ps", "-o", "%cpu=,rss=", "-p", strconv.Itoa(pid)).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to sample process %d: %w", pid, err)
+	}
+
+	fields := strings.Fields(string(out))
+	if len(fields) < 2 {
+		return nil, fmt.Errorf("unexpected ps output for pid %d", pid)
+	}
+
+	metrics := &ProcessMetrics{}
+	metrics.CPUPercent, _ = strconv.ParseFloat(fields[0], 64)
+	metrics.MemoryKB, _ = strconv.ParseFloat(fields[1], 64)
+	return metrics, nil
+}
@@ -0,0 +1,107 @@
+// file: internal/suprvisor/metrics_test.go
+//
+// The /proc parsers are fed fixture bytes captured from a real Linux
+// box, so the sampling path is exercised without needing a live process
+// (or even a Linux kernel) under the test runner.
+
+package suprvisor
+
+import (
+	"testing"
+	"time"
+)
+
+// A realistic /proc/<pid>/stat line. The comm field "(nix tea) svc)"
+// deliberately contains spaces and a closing paren — parsing must key
+// off the LAST ")", not the first.
+const statFixture = "12345 ((nix tea) svc) S 1 12345 12345 0 -1 4194304 2516 0 0 0 42 17 0 0 20 0 4 0 8231 225492992 1876 18446744073709551615 1 1 0 0 0 0 0 0 0 0 0 0 17 3 0 0 0 0 0\n"
+
+// A /proc/<pid>/statm line: size resident shared text lib data dt
+const statmFixture = "55052 1876 1333 11 0 4871 0\n"
+
+func TestParseStatCPUTicks(t *testing.T) {
+	ticks, err := parseStatCPUTicks([]byte(statFixture))
+	if err != nil {
+		t.Fatalf("parseStatCPUTicks: %v", err)
+	}
+	// utime=42 + stime=17
+	if ticks != 59 {
+		t.Errorf("ticks = %d, want 59", ticks)
+	}
+}
+
+func TestParseStatCPUTicksMalformed(t *testing.T) {
+	for _, raw := range []string{
+		"",
+		"12345 no-comm-parens S 1",
+		"12345 (short) S 1 2 3",
+	} {
+		if _, err := parseStatCPUTicks([]byte(raw)); err == nil {
+			t.Errorf("parseStatCPUTicks(%q) succeeded, want error", raw)
+		}
+	}
+}
+
+func TestParseStatmRSSKB(t *testing.T) {
+	// 1876 resident pages at 4 KB each
+	kb, err := parseStatmRSSKB([]byte(statmFixture), 4096)
+	if err != nil {
+		t.Fatalf("parseStatmRSSKB: %v", err)
+	}
+	if kb != 1876*4 {
+		t.Errorf("rss = %.0f KB, want %d", kb, 1876*4)
+	}
+
+	if _, err := parseStatmRSSKB([]byte("55052\n"), 4096); err == nil {
+		t.Error("parseStatmRSSKB with one field succeeded, want error")
+	}
+}
+
+func TestDropCPUSampleForgetsBaseline(t *testing.T) {
+	const pid = 1<<30 + 1
+
+	// Establish a baseline, then reap the pid
+	cpuPercent(pid, 100, time.Now())
+	dropCPUSample(pid)
+
+	cpuSampleMu.Lock()
+	_, exists := cpuSamples[pid]
+	cpuSampleMu.Unlock()
+	if exists {
+		t.Error("baseline still tracked after dropCPUSample")
+	}
+
+	// A recycled pid starts from scratch rather than inheriting the dead
+	// process's jiffy count
+	if got := cpuPercent(pid, 5, time.Now()); got != 0 {
+		t.Errorf("first sample after drop = %.1f%%, want 0", got)
+	}
+	dropCPUSample(pid)
+
+	// Dropping an untracked pid is a no-op
+	dropCPUSample(pid)
+}
+
+func TestCPUPercentDelta(t *testing.T) {
+	// An unlikely pid so the package-level baseline map stays clean
+	// across tests
+	const pid = 1 << 30
+
+	start := time.Now()
+
+	// First sample has no baseline and must report 0
+	if got := cpuPercent(pid, 100, start); got != 0 {
+		t.Errorf("first sample = %.1f%%, want 0", got)
+	}
+
+	// 50 jiffies over 1s at USER_HZ=100 is half a core
+	if got := cpuPercent(pid, 150, start.Add(time.Second)); got != 50 {
+		t.Errorf("second sample = %.1f%%, want 50", got)
+	}
+
+	// A pid reuse (ticks going backwards) resets to 0 instead of
+	// underflowing
+	if got := cpuPercent(pid, 10, start.Add(2*time.Second)); got != 0 {
+		t.Errorf("ticks-went-backwards sample = %.1f%%, want 0", got)
+	}
+}
@@ -0,0 +1,106 @@
+// file: internal/suprvisor/multistream.go
+//
+// merged log streaming across every running package, docker-compose
+// style: one channel carrying lines tagged with the package that
+// produced them
+
+package suprvisor
+
+import (
+	"sync"
+	"time"
+)
+
+// LogLine pairs one log line with the package key that produced it
+type LogLine struct {
+	Key  string
+	Text string
+}
+
+// How often StreamAllOutput rescans for packages that started after the
+// stream was opened
+const streamAllRescanInterval = 2 * time.Second
+
+// subscribeLogs registers a live log channel on the runnable. The channel
+// is closed by closeLogSubs when the last replica exits.
+func (r *Runnable) subscribeLogs() chan string {
+	ch := make(chan string, 100)
+	r.logMu.Lock()
+	r.logSubs = append(r.logSubs, ch)
+	r.logMu.Unlock()
+	return ch
+}
+
+// StreamAllOutput merges the live log streams of every running package
+// into a single channel. Packages that start after the call are picked up
+// by a periodic rescan; packages that stop simply go quiet (and are
+// re-attached if they come back). The returned cancel function tears the
+// stream down and must be called when the caller detaches.
+func (s *UnderSupervision) StreamAllOutput() (<-chan LogLine, func()) {
+	out := make(chan LogLine, 256)
+	done := make(chan struct{})
+
+	// Tracks which packages we currently have a live subscription on so
+	// the rescan doesn't double-attach
+	var attachedMu sync.Mutex
+	attached := make(map[string]bool)
+
+	attach := func() {
+		s.mu.RLock()
+		running := make(map[string]*Runnable)
+		for key, runnable := range s.items {
+			if runnable.Status == "running" || runnable.Status == "starting" {
+				running[key] = runnable
+			}
+		}
+		s.mu.RUnlock()
+
+		for key, runnable := range running {
+			attachedMu.Lock()
+			if attached[key] {
+				attachedMu.Unlock()
+				continue
+			}
+			attached[key] = true
+			attachedMu.Unlock()
+
+			ch := runnable.subscribeLogs()
+			go func(key string, ch chan string) {
+				for line := range ch {
+					select {
+					case out <- LogLine{Key: key, Text: line}:
+					case <-done:
+						return
+					}
+				}
+
+				// Channel closed: the package stopped. Drop it from the
+				// attached set so a restart gets picked up again.
+				attachedMu.Lock()
+				delete(attached, key)
+				attachedMu.Unlock()
+			}(key, ch)
+		}
+	}
+
+	attach()
+	go func() {
+		ticker := time.NewTicker(streamAllRescanInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				attach()
+			}
+		}
+	}()
+
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() { close(done) })
+	}
+
+	return out, cancel
+}
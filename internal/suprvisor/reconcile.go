@@ -0,0 +1,92 @@
+package suprvisor
+
+import (
+	"fmt"
+	"syscall"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+// StartReconciler launches a background loop that keeps reported status
+// in line with reality. If a supervised process dies in a way the wait
+// goroutine misses (or an adopted process exits), Status can go stale;
+// the reconciler periodically probes each running package's PIDs and
+// corrects the drift. An interval <= 0 disables it.
+func (s *UnderSupervision) StartReconciler(interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			s.reconcile()
+		}
+	}()
+}
+
+// pidAlive reports whether a process with the given PID still exists.
+// Signal 0 performs the existence check without delivering anything;
+// EPERM means the process is there but owned by someone else, which
+// still counts as alive.
+func pidAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	err := syscall.Kill(pid, 0)
+	return err == nil || err == syscall.EPERM
+}
+
+// reconcile is one pass of the reconciler: drop replica entries whose
+// processes no longer exist and correct the package status, emitting a
+// state-change event so listeners hear about the correction. Zombies
+// not yet reaped by cmd.Wait still probe as alive, so a pass racing the
+// wait goroutine won't produce false positives.
+func (s *UnderSupervision) reconcile() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, runnable := range s.items {
+		if runnable.Status != "running" && runnable.Status != "starting" {
+			continue
+		}
+
+		live := make([]*ProcessState, 0, len(runnable.procs))
+		var lost []int
+		for _, proc := range runnable.procs {
+			pid := 0
+			if proc.Cmd != nil && proc.Cmd.Process != nil {
+				pid = proc.Cmd.Process.Pid
+			}
+			if pidAlive(pid) {
+				live = append(live, proc)
+			} else {
+				lost = append(lost, pid)
+			}
+		}
+		if len(lost) == 0 {
+			continue
+		}
+		runnable.procs = live
+
+		if len(runnable.procs) == 0 {
+			runnable.Status = "stopped"
+			runnable.PID = 0
+			runnable.process = nil
+		} else if runnable.process == nil || !pidAlive(runnable.PID) {
+			// Keep the reported PID pointing at a live replica
+			runnable.process = runnable.procs[0]
+			runnable.PID = runnable.procs[0].Cmd.Process.Pid
+		}
+
+		log.Warn("Reconciled stale process state", "key", key, "lostPids", lost, "status", runnable.Status)
+		s.recordEvent(runnable, Event{
+			Type:   "crashed",
+			Key:    key,
+			Name:   runnable.Name,
+			Detail: fmt.Sprintf("pid(s) %v no longer exist; status corrected by reconciler", lost),
+		})
+	}
+}
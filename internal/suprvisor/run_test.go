@@ -0,0 +1,101 @@
+// file: internal/suprvisor/run_test.go
+//
+// Covers the double-start guard: the status check and the "starting"
+// claim happen under one lock hold, so a burst of concurrent Run calls
+// for the same key must launch exactly one process.
+
+package suprvisor
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestConcurrentRunStartsOneProcess(t *testing.T) {
+	s := NewSupervisor()
+	s.items["svc"] = &Runnable{
+		Name:       "svc",
+		BinaryPath: "/bin/sleep",
+		Status:     "stopped",
+		config:     &RunConfig{Args: []string{"30"}},
+	}
+
+	const callers = 8
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		succeeded int
+	)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := s.Run("svc"); err == nil {
+				mu.Lock()
+				succeeded++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if succeeded != 1 {
+		t.Errorf("%d of %d concurrent Run calls succeeded, want exactly 1", succeeded, callers)
+	}
+
+	s.mu.RLock()
+	procs := len(s.items["svc"].procs)
+	status := s.items["svc"].Status
+	pid := s.items["svc"].PID
+	s.mu.RUnlock()
+
+	if procs != 1 {
+		t.Errorf("%d processes tracked, want 1", procs)
+	}
+	if status != "running" {
+		t.Errorf("status = %q, want running", status)
+	}
+	if pid == 0 {
+		t.Error("PID not recorded for the started process")
+	}
+
+	// A late Run against the now-running package is refused too
+	if err := s.Run("svc"); err == nil {
+		t.Error("Run against a running package succeeded, want error")
+	}
+
+	if err := s.Stop("svc"); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+
+	// Give the exit monitor a moment to record the stop
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		s.mu.RLock()
+		status := s.items["svc"].Status
+		s.mu.RUnlock()
+		if status == "stopped" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("status = %q after Stop, want stopped", status)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestRunUnknownAndUnbuiltPackages(t *testing.T) {
+	s := NewSupervisor()
+
+	if err := s.Run("nope"); err == nil {
+		t.Error("Run for an unknown key succeeded, want error")
+	}
+
+	// Tracked but never built: fail with the actionable message, not an
+	// exec error for an empty path
+	s.items["unbuilt"] = &Runnable{Name: "unbuilt", Status: "stopped"}
+	if err := s.Run("unbuilt"); err == nil {
+		t.Error("Run for an unbuilt package succeeded, want error")
+	}
+}
@@ -0,0 +1,65 @@
+// file: internal/suprvisor/sanitize_test.go
+//
+// sanitizeLogLine is the last line of defense between a misbehaving
+// child process and the client terminal: runaway lines get truncated
+// and binary spew gets replaced outright.
+
+package suprvisor
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSanitizeLogLinePassesNormalOutput(t *testing.T) {
+	for _, line := range []string{
+		"",
+		"listening on :8080",
+		"unicode is fine: héllo ✓ 日本語",
+	} {
+		if got := sanitizeLogLine(line); got != line {
+			t.Errorf("sanitizeLogLine(%q) = %q, want unchanged", line, got)
+		}
+	}
+}
+
+func TestSanitizeLogLineTruncatesMegabyteLine(t *testing.T) {
+	// A single 1 MB line — the kind a process emitting JSON without
+	// newlines produces
+	huge := strings.Repeat("x", 1024*1024)
+
+	got := sanitizeLogLine(huge)
+	if len(got) > maxLogLineBytes+len(" ...[truncated]") {
+		t.Errorf("truncated line is %d bytes, want at most %d", len(got), maxLogLineBytes+len(" ...[truncated]"))
+	}
+	if !strings.HasSuffix(got, " ...[truncated]") {
+		t.Error("truncated line missing the truncation marker")
+	}
+	if !strings.HasPrefix(got, huge[:maxLogLineBytes]) {
+		t.Error("truncation did not keep the leading content")
+	}
+
+	// A line exactly at the limit is left alone
+	exact := strings.Repeat("y", maxLogLineBytes)
+	if got := sanitizeLogLine(exact); got != exact {
+		t.Error("line at exactly maxLogLineBytes was modified")
+	}
+}
+
+func TestSanitizeLogLineReplacesBinaryBlob(t *testing.T) {
+	// NUL bytes: a process cat-ing a binary into its stdout
+	withNul := "ELF\x00\x01\x02garbage"
+	got := sanitizeLogLine(withNul)
+	if !strings.Contains(got, "binary output omitted") {
+		t.Errorf("NUL-bearing line not replaced: %q", got)
+	}
+	if !strings.Contains(got, "13 bytes") {
+		t.Errorf("replacement %q missing the original byte count", got)
+	}
+
+	// Invalid UTF-8 without NULs is binary too
+	invalid := string([]byte{0xff, 0xfe, 0xfd})
+	if got := sanitizeLogLine(invalid); !strings.Contains(got, "binary output omitted") {
+		t.Errorf("invalid UTF-8 not replaced: %q", got)
+	}
+}
@@ -1,13 +1,20 @@
 package suprvisor
 
 import (
+	"bufio"
 	"context"
 	"fmt"
 	"io"
+	"os"
 	"os/exec"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
+	"unicode/utf8"
 	"walross/nixtea/internal/nixapi"
 
 	"github.com/charmbracelet/log"
@@ -16,15 +23,255 @@ import (
 type UnderSupervision struct {
 	items map[string]*Runnable
 	mu    sync.RWMutex
+
+	// Single-flight state for hydrate passes: a burst of concurrent
+	// hydrate requests coalesces onto the one in-progress build instead
+	// of rebuilding in parallel.
+	hydrateMu   sync.Mutex
+	hydrating   bool
+	hydrateDone chan struct{}
+	hydrateErr  error
+
+	// Per-package results of the most recent hydrate pass
+	lastReport []BuildReportEntry
+
+	// Fan-out bus for state-change events
+	events *eventBus
+
+	// Declared startup dependencies: key -> packages that must be
+	// running first
+	deps map[string][]string
+
+	// Optional hook that loads persisted per-package run configuration;
+	// called at the end of each hydrate pass
+	configLoader func() (map[string]RunConfig, error)
+
+	// Where per-package GC roots are registered so built outputs survive
+	// nix garbage collection; empty disables root management
+	gcRootsDir string
+
+	// Detached update jobs ('ctx update --detach'), kept after they
+	// finish so their outcome can still be polled
+	jobsMu sync.Mutex
+	jobs   map[string]*UpdateJob
+	jobSeq int
+}
+
+// RunConfig is the persisted run-time configuration for one package
+type RunConfig struct {
+	Args          []string `json:"args,omitempty"`
+	Env           []string `json:"env,omitempty"`
+	Replicas      int      `json:"replicas,omitempty"`
+	RestartPolicy string   `json:"restart_policy,omitempty"`
+
+	// Optional friendly display name shown in place of the flake's
+	// package name
+	Label string `json:"label,omitempty"`
+
+	// Startup grace window in seconds: the package reports "starting"
+	// until it has stayed up this long, so slow-booting services aren't
+	// prematurely treated as healthy. 0 means report "running"
+	// immediately.
+	StartupGraceSeconds int `json:"startup_grace_seconds,omitempty"`
+}
+
+// SetConfigLoader registers the hook used to load persisted per-package
+// run configuration during hydrate
+func (s *UnderSupervision) SetConfigLoader(loader func() (map[string]RunConfig, error)) {
+	s.hydrateMu.Lock()
+	s.configLoader = loader
+	s.hydrateMu.Unlock()
+}
+
+// applyStoredConfigs attaches persisted run configuration to the current
+// runnables. Callers must hold s.mu.
+func (s *UnderSupervision) applyStoredConfigs() {
+	s.hydrateMu.Lock()
+	loader := s.configLoader
+	s.hydrateMu.Unlock()
+
+	if loader == nil {
+		return
+	}
+
+	configs, err := loader()
+	if err != nil {
+		log.Warn("Failed to load package run configuration", "error", err)
+		return
+	}
+
+	for key, cfg := range configs {
+		if runnable, exists := s.items[key]; exists {
+			c := cfg
+			runnable.config = &c
+		}
+	}
+}
+
+// SetGCRootsDir enables GC-root registration for built packages under
+// the given directory
+func (s *UnderSupervision) SetGCRootsDir(dir string) {
+	s.hydrateMu.Lock()
+	s.gcRootsDir = dir
+	s.hydrateMu.Unlock()
+}
+
+// registerGCRoot pins a freshly-built store path against garbage
+// collection. Best effort: a failed root registration shouldn't fail
+// the build that produced the path.
+func (s *UnderSupervision) registerGCRoot(key, storePath string) {
+	s.hydrateMu.Lock()
+	dir := s.gcRootsDir
+	s.hydrateMu.Unlock()
+
+	if dir == "" || storePath == "" {
+		return
+	}
+	if err := nixapi.AddGCRoot(dir, key, storePath); err != nil {
+		log.Warn("Failed to register gc root", "key", key, "error", err)
+	}
+}
+
+// GCRootsDir returns the configured root directory ("" when disabled)
+func (s *UnderSupervision) GCRootsDir() string {
+	s.hydrateMu.Lock()
+	defer s.hydrateMu.Unlock()
+	return s.gcRootsDir
+}
+
+// beginHydrate marks a hydrate pass as started. If one is already in
+// progress it returns the in-flight pass's done channel and false.
+func (s *UnderSupervision) beginHydrate() (chan struct{}, bool) {
+	s.hydrateMu.Lock()
+	defer s.hydrateMu.Unlock()
+
+	if s.hydrating {
+		return s.hydrateDone, false
+	}
+
+	s.hydrating = true
+	s.hydrateDone = make(chan struct{})
+	return s.hydrateDone, true
+}
+
+// endHydrate records the result of a hydrate pass and wakes any callers
+// waiting on it
+func (s *UnderSupervision) endHydrate(err error) {
+	s.hydrateMu.Lock()
+	s.hydrating = false
+	s.hydrateErr = err
+	close(s.hydrateDone)
+	s.hydrateMu.Unlock()
+}
+
+// IsHydrating reports whether a hydrate/build pass is in progress
+func (s *UnderSupervision) IsHydrating() bool {
+	s.hydrateMu.Lock()
+	defer s.hydrateMu.Unlock()
+	return s.hydrating
 }
 
 type Runnable struct {
 	Name       string
 	BinaryPath string
+	StorePath  string
+
+	// Which flake repo this package came from; packages from a
+	// previously-active repo can linger in the supervisor after a
+	// context switch
+	RepoURL    string
 	Status     string
 	PID        int
 	buildError error
 	process    *ProcessState
+	env        []string
+
+	// Additional replica processes beyond the first when a package is
+	// scaled out; each entry tracks one running instance
+	procs []*ProcessState
+
+	// Persisted run configuration loaded from the database, if any
+	config *RunConfig
+
+	// Retained output and live log subscribers. The buffer survives the
+	// process exiting so logs can still be inspected after a crash.
+	logMu      sync.Mutex
+	logRecords []RetainedLine
+	logSubs    []chan string
+
+	// Index into logRecords where the current (or most recent) run's
+	// output begins; everything before it belongs to prior runs
+	runStart int
+
+	// Recent lifecycle events (started, stopped, crashed, builds) so the
+	// story of what happened survives after messages scroll away
+	histMu  sync.Mutex
+	history []Event
+}
+
+// Maximum lifecycle events retained per package
+const maxEventHistory = 50
+
+// recordEvent appends a lifecycle event to the package's retained
+// history and broadcasts it on the event bus
+func (s *UnderSupervision) recordEvent(runnable *Runnable, evt Event) {
+	evt.Timestamp = time.Now()
+
+	runnable.histMu.Lock()
+	runnable.history = append(runnable.history, evt)
+	if len(runnable.history) > maxEventHistory {
+		runnable.history = runnable.history[len(runnable.history)-maxEventHistory:]
+	}
+	runnable.histMu.Unlock()
+
+	s.events.emit(evt)
+}
+
+// RetainedLine is one captured log line with its origin stream and
+// capture time, so structured consumers ('pkgs logs --json') don't have
+// to parse the rendered text
+type RetainedLine struct {
+	Time   time.Time
+	Stream string
+	Text   string
+}
+
+// addLogLine appends a line to the retained buffer, tagged with the
+// stream it came from, and forwards it to any live subscribers without
+// blocking on slow readers.
+func (r *Runnable) addLogLine(stream, text string) {
+	r.logMu.Lock()
+	r.logRecords = append(r.logRecords, RetainedLine{Time: time.Now(), Stream: stream, Text: text})
+	subs := make([]chan string, len(r.logSubs))
+	copy(subs, r.logSubs)
+	r.logMu.Unlock()
+
+	for _, ch := range subs {
+		select {
+		case ch <- text:
+		default:
+		}
+	}
+}
+
+// markRunStart records where the current run begins in the retained
+// buffer, so 'pkgs logs --since-start' can skip prior runs' output
+func (r *Runnable) markRunStart() {
+	r.logMu.Lock()
+	r.runStart = len(r.logRecords)
+	r.logMu.Unlock()
+}
+
+// closeLogSubs closes all live subscriber channels, signalling end of stream
+func (r *Runnable) closeLogSubs() {
+	r.logMu.Lock()
+	subs := r.logSubs
+	r.logSubs = nil
+	r.logMu.Unlock()
+
+	for _, ch := range subs {
+		close(ch)
+	}
 }
 
 type BuildError struct {
@@ -32,12 +279,25 @@ type BuildError struct {
 	Success []string
 }
 
+// BuildReportEntry records the outcome of building one package during a
+// hydrate pass, in a shape suitable for serializing to external tooling.
+type BuildReportEntry struct {
+	Key       string  `json:"key"`
+	Name      string  `json:"name"`
+	Success   bool    `json:"success"`
+	Skipped   bool    `json:"skipped,omitempty"`
+	Error     string  `json:"error,omitempty"`
+	StorePath string  `json:"store_path,omitempty"`
+	Duration  float64 `json:"duration_seconds"`
+}
+
 type ProcessState struct {
 	Cmd       *exec.Cmd
 	Done      chan error
 	StartTime time.Time
-	outReader *io.PipeReader
-	outWriter *io.PipeWriter
+
+	// Which replica this process is (0 for the first/only instance)
+	Replica int
 }
 
 func (e *BuildError) Error() string {
@@ -46,18 +306,54 @@ func (e *BuildError) Error() string {
 
 func NewSupervisor() *UnderSupervision {
 	return &UnderSupervision{
-		items: make(map[string]*Runnable),
+		items:  make(map[string]*Runnable),
+		events: newEventBus(),
 	}
 }
 
 // Hydrate updates the supervisor's state with packages from the provided repo URL.
 // It creates a Runnable entry for each package in the flake and builds them.
 func (s *UnderSupervision) Hydrate(repoURL string) error {
+	done, started := s.beginHydrate()
+	if !started {
+		log.Info("Hydrate already in progress, waiting for it to finish")
+		<-done
+		return s.hydrateErr
+	}
+
+	err := s.hydrate(repoURL)
+	s.endHydrate(err)
+	return err
+}
+
+// carryOverRunnable merges one package into a hydrate pass: an entry
+// from the previous pass is reused — so retained logs, lifecycle
+// history and live process handles survive — while a new package gets a
+// fresh stopped entry. The boolean reports whether an existing entry
+// was carried forward.
+func carryOverRunnable(previous map[string]*Runnable, key, name, repoURL string) (*Runnable, bool) {
+	runnable, existed := previous[key]
+	if existed {
+		runnable.Name = name
+	} else {
+		runnable = &Runnable{
+			Name:   name,
+			Status: "stopped",
+			PID:    0,
+		}
+	}
+	runnable.RepoURL = repoURL
+	return runnable, existed
+}
+
+// hydrate performs a single build pass. Callers must hold the
+// single-flight slot via beginHydrate.
+func (s *UnderSupervision) hydrate(repoURL string) error {
 
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	client := nixapi.NewClient()
+	client := nixapi.NewClient(nixapi.CurrentSystem())
 	defer client.Close()
 
 	packages, err := client.GetSystemPackages(repoURL)
@@ -65,7 +361,13 @@ func (s *UnderSupervision) Hydrate(repoURL string) error {
 		return fmt.Errorf("failed to get packages: %w", err)
 	}
 
-	// Reset the items map to start fresh
+	// Remember the previous entries: unchanged packages whose store paths
+	// are still valid can skip the rebuild, and existing Runnables are
+	// carried forward so retained logs, lifecycle history and live
+	// process handles survive the hydrate
+	previous := s.items
+
+	// Rebuild the items map; packages removed from the flake drop out here
 	s.items = make(map[string]*Runnable)
 
 	buildError := &BuildError{
@@ -73,17 +375,40 @@ func (s *UnderSupervision) Hydrate(repoURL string) error {
 		Success: make([]string, 0),
 	}
 
+	report := make([]BuildReportEntry, 0, len(packages))
+
 	for key, pkg := range packages {
 		log.Info("Building package", "name", pkg.Name, "key", key)
 
-		runnable := &Runnable{
-			Name:   pkg.Name,
-			Status: "stopped",
-			PID:    0,
-		}
+		// Carry the existing entry forward rather than starting fresh;
+		// only its build metadata is refreshed below
+		runnable, existed := carryOverRunnable(previous, key, pkg.Name, repoURL)
 		s.items[key] = runnable
 
+		// Reuse the existing output when it's still in the store
+		if existed && client.StorePathExists(runnable.StorePath) {
+			buildError.Success = append(buildError.Success, key)
+			report = append(report, BuildReportEntry{
+				Key:       key,
+				Name:      pkg.Name,
+				Success:   true,
+				Skipped:   true,
+				StorePath: runnable.StorePath,
+			})
+			log.Info("Skipping build, store path still valid",
+				"name", pkg.Name,
+				"key", key,
+				"storePath", runnable.StorePath)
+			continue
+		}
+
+		buildStart := time.Now()
 		buildResult, err := client.BuildPkg(repoURL, key)
+		entry := BuildReportEntry{
+			Key:      key,
+			Name:     pkg.Name,
+			Duration: time.Since(buildStart).Seconds(),
+		}
 		if err != nil {
 			log.Error("Failed to build package",
 				"name", pkg.Name,
@@ -92,12 +417,29 @@ func (s *UnderSupervision) Hydrate(repoURL string) error {
 
 			buildError.Failed[key] = err
 			runnable.buildError = err
-			runnable.Status = "build_failed"
+			// A still-running process keeps serving with its old binary;
+			// only idle packages flip to build_failed
+			if runnable.Status != "running" {
+				runnable.Status = "build_failed"
+			}
+			entry.Error = err.Error()
+			report = append(report, entry)
+			s.recordEvent(runnable, Event{Type: "build_failed", Key: key, Name: pkg.Name, Detail: err.Error()})
 			continue
 		}
 
 		runnable.BinaryPath = buildResult.BinaryPath
+		runnable.StorePath = buildResult.StorePath
+		runnable.buildError = nil
+		if runnable.Status == "build_failed" {
+			runnable.Status = "stopped"
+		}
 		buildError.Success = append(buildError.Success, key)
+		entry.Success = true
+		entry.StorePath = buildResult.StorePath
+		report = append(report, entry)
+		s.recordEvent(runnable, Event{Type: "build_succeeded", Key: key, Name: pkg.Name})
+		s.registerGCRoot(key, buildResult.StorePath)
 
 		log.Info("Successfully built package",
 			"name", pkg.Name,
@@ -105,6 +447,9 @@ func (s *UnderSupervision) Hydrate(repoURL string) error {
 			"binary", buildResult.BinaryPath)
 	}
 
+	s.lastReport = report
+	s.applyStoredConfigs()
+
 	if len(buildError.Failed) > 0 {
 		return buildError
 	}
@@ -114,8 +459,79 @@ func (s *UnderSupervision) Hydrate(repoURL string) error {
 	return nil
 }
 
+// RebuildPackage rebuilds a single package and updates just its Runnable,
+// leaving the rest of the supervisor state untouched. This is much faster
+// than a full hydrate pass when only one package changed.
+func (s *UnderSupervision) RebuildPackage(repoURL string, key string) error {
+	client := nixapi.NewClient(nixapi.CurrentSystem())
+	defer client.Close()
+
+	packages, err := client.GetSystemPackages(repoURL)
+	if err != nil {
+		return fmt.Errorf("failed to get packages: %w", err)
+	}
+
+	pkg, exists := packages[key]
+	if !exists {
+		return fmt.Errorf("package %s not found in flake", key)
+	}
+
+	s.mu.Lock()
+	runnable, tracked := s.items[key]
+	if !tracked {
+		runnable = &Runnable{
+			Name:   pkg.Name,
+			Status: "stopped",
+			PID:    0,
+		}
+		s.items[key] = runnable
+	}
+	s.mu.Unlock()
+
+	log.Info("Rebuilding package", "name", pkg.Name, "key", key)
+
+	buildResult, err := client.BuildPkg(repoURL, key)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err != nil {
+		log.Error("Failed to build package",
+			"name", pkg.Name,
+			"key", key,
+			"error", err)
+		runnable.buildError = err
+		if runnable.Status != "running" {
+			runnable.Status = "build_failed"
+		}
+		return fmt.Errorf("failed to build %s: %w", key, err)
+	}
+
+	runnable.buildError = nil
+	runnable.BinaryPath = buildResult.BinaryPath
+	runnable.StorePath = buildResult.StorePath
+
+	log.Info("Successfully built package",
+		"name", pkg.Name,
+		"key", key,
+		"binary", buildResult.BinaryPath)
+
+	return nil
+}
+
 // HydrateWithTimeout wraps Hydrate with timeout and debug logging
 func (s *UnderSupervision) HydrateWithTimeout(repoURL string, timeout time.Duration) error {
+	inflight, started := s.beginHydrate()
+	if !started {
+		log.Info("Hydrate already in progress, waiting for it to finish")
+		select {
+		case <-inflight:
+			return s.hydrateErr
+		case <-time.After(timeout):
+			return fmt.Errorf("hydration timed out after %v", timeout)
+		}
+	}
+
 	// Create a context with timeout
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
@@ -123,18 +539,24 @@ func (s *UnderSupervision) HydrateWithTimeout(repoURL string, timeout time.Durat
 	// Create channel for result
 	done := make(chan error, 1)
 
-	// Run Hydrate in goroutine with detailed logging
+	// Run Hydrate in goroutine with detailed logging. The single-flight
+	// slot is released by the goroutine so a timed-out caller can't start
+	// another build while this one is still running.
 	go func() {
+		reportResult := func(err error) {
+			s.endHydrate(err)
+			done <- err
+		}
 		log.Info("Starting hydration process", "repoURL", repoURL)
 
 		// Initialize Nix client
-		client := nixapi.NewClient()
+		client := nixapi.NewClient(nixapi.CurrentSystem())
 		defer client.Close()
 
 		log.Info("Fetching system packages")
 		packages, err := client.GetSystemPackages(repoURL)
 		if err != nil {
-			done <- fmt.Errorf("failed to get packages: %w", err)
+			reportResult(fmt.Errorf("failed to get packages: %w", err))
 			return
 		}
 		log.Info("Retrieved packages", "count", len(packages))
@@ -142,7 +564,12 @@ func (s *UnderSupervision) HydrateWithTimeout(repoURL string, timeout time.Durat
 		s.mu.Lock()
 		defer s.mu.Unlock()
 
-		// Reset items map
+		// Remember previous entries: still-valid store paths skip the
+		// rebuild, and existing Runnables are carried forward so their
+		// logs and process handles survive
+		previous := s.items
+
+		// Rebuild the items map
 		s.items = make(map[string]*Runnable)
 
 		buildError := &BuildError{
@@ -153,11 +580,12 @@ func (s *UnderSupervision) HydrateWithTimeout(repoURL string, timeout time.Durat
 		// Track progress
 		total := len(packages)
 		processed := 0
+		report := make([]BuildReportEntry, 0, total)
 
 		for key, pkg := range packages {
 			select {
 			case <-ctx.Done():
-				done <- fmt.Errorf("hydration timed out after %v", timeout)
+				reportResult(fmt.Errorf("hydration timed out after %v", timeout))
 				return
 			default:
 				log.Info("Building package",
@@ -165,14 +593,35 @@ func (s *UnderSupervision) HydrateWithTimeout(repoURL string, timeout time.Durat
 					"key", key,
 					"progress", fmt.Sprintf("%d/%d", processed+1, total))
 
-				runnable := &Runnable{
-					Name:   pkg.Name,
-					Status: "stopped",
-					PID:    0,
-				}
+				// Carry the existing entry forward rather than starting
+				// fresh; only its build metadata is refreshed below
+				runnable, existed := carryOverRunnable(previous, key, pkg.Name, repoURL)
 				s.items[key] = runnable
 
+				if existed && client.StorePathExists(runnable.StorePath) {
+					buildError.Success = append(buildError.Success, key)
+					report = append(report, BuildReportEntry{
+						Key:       key,
+						Name:      pkg.Name,
+						Success:   true,
+						Skipped:   true,
+						StorePath: runnable.StorePath,
+					})
+					log.Info("Skipping build, store path still valid",
+						"name", pkg.Name,
+						"key", key,
+						"storePath", runnable.StorePath)
+					processed++
+					continue
+				}
+
+				buildStart := time.Now()
 				buildResult, err := client.BuildPkg(repoURL, key)
+				entry := BuildReportEntry{
+					Key:      key,
+					Name:     pkg.Name,
+					Duration: time.Since(buildStart).Seconds(),
+				}
 				if err != nil {
 					log.Error("Failed to build package",
 						"name", pkg.Name,
@@ -180,21 +629,40 @@ func (s *UnderSupervision) HydrateWithTimeout(repoURL string, timeout time.Durat
 						"error", err)
 					buildError.Failed[key] = err
 					runnable.buildError = err
-					runnable.Status = "build_failed"
+					// A still-running process keeps serving with its old
+					// binary; only idle packages flip to build_failed
+					if runnable.Status != "running" {
+						runnable.Status = "build_failed"
+					}
+					entry.Error = err.Error()
+					s.recordEvent(runnable, Event{Type: "build_failed", Key: key, Name: pkg.Name, Detail: err.Error()})
 				} else {
 					runnable.BinaryPath = buildResult.BinaryPath
+					runnable.StorePath = buildResult.StorePath
+					runnable.buildError = nil
+					if runnable.Status == "build_failed" {
+						runnable.Status = "stopped"
+					}
 					buildError.Success = append(buildError.Success, key)
+					entry.Success = true
+					entry.StorePath = buildResult.StorePath
+					s.recordEvent(runnable, Event{Type: "build_succeeded", Key: key, Name: pkg.Name})
+					s.registerGCRoot(key, buildResult.StorePath)
 					log.Info("Successfully built package",
 						"name", pkg.Name,
 						"key", key,
 						"binary", buildResult.BinaryPath)
 				}
+				report = append(report, entry)
 				processed++
 			}
 		}
 
+		s.lastReport = report
+		s.applyStoredConfigs()
+
 		if len(buildError.Failed) > 0 {
-			done <- buildError
+			reportResult(buildError)
 			return
 		}
 
@@ -202,7 +670,7 @@ func (s *UnderSupervision) HydrateWithTimeout(repoURL string, timeout time.Durat
 			"total", total,
 			"succeeded", len(buildError.Success),
 			"failed", len(buildError.Failed))
-		done <- nil
+		reportResult(nil)
 	}()
 
 	// Wait for either completion or timeout
@@ -216,103 +684,303 @@ func (s *UnderSupervision) HydrateWithTimeout(repoURL string, timeout time.Durat
 
 // Run starts a package by its key
 func (s *UnderSupervision) Run(key string) error {
+	return s.RunReplicas(key, 1)
+}
+
+// RunReplicas starts count instances of a package. Each replica gets its
+// own process group and PID; log lines are tagged with the replica index
+// when more than one instance is running.
+func (s *UnderSupervision) RunReplicas(key string, count int) error {
 	s.mu.Lock()
 	runnable, exists := s.items[key]
-	s.mu.Unlock()
-
 	if !exists {
+		s.mu.Unlock()
 		return fmt.Errorf("package %s not found", key)
 	}
 
-	if runnable.Status == "running" {
+	// Fall back to the persisted replica count unless the caller asked
+	// for a specific one
+	if count < 1 {
+		count = 1
+	}
+	if count == 1 && runnable.config != nil && runnable.config.Replicas > 1 {
+		count = runnable.config.Replicas
+	}
+
+	// The status check and the claim below happen under one lock hold so
+	// two concurrent Run calls can't both pass the guard and double-start
+	// the package
+	switch runnable.Status {
+	case "running":
+		s.mu.Unlock()
 		return fmt.Errorf("package %s is already running", key)
+	case "starting":
+		s.mu.Unlock()
+		return fmt.Errorf("package %s is already starting", key)
+	case "build_failed":
+		err := runnable.buildError
+		s.mu.Unlock()
+		return fmt.Errorf("package %s failed to build: %v (run 'nixtea repos update' to retry)", key, err)
 	}
 
-	cmd := exec.Command(runnable.BinaryPath)
+	// A missing binary means the package was never built (or its store
+	// path was collected); fail with something actionable instead of the
+	// confusing error exec.Command produces for an empty path
+	if err := checkBinary(runnable.BinaryPath); err != nil {
+		s.mu.Unlock()
+		return fmt.Errorf("package %s: %w", key, err)
+	}
+
+	// Claim the start; startReplica flips this to "running" once the
+	// first process is up
+	runnable.Status = "starting"
+	s.mu.Unlock()
+
+	runnable.markRunStart()
+
+	for i := 0; i < count; i++ {
+		if err := s.startReplica(key, runnable, i, count); err != nil {
+			// Unwind any replicas that did start and release the claim
+			if i > 0 {
+				_ = s.Stop(key)
+			}
+			s.mu.Lock()
+			if len(runnable.procs) == 0 {
+				runnable.Status = "stopped"
+			}
+			s.mu.Unlock()
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkBinary verifies a package's build output actually exists and is
+// executable before we try to exec it
+func checkBinary(path string) error {
+	if path == "" {
+		return fmt.Errorf("binary not built; run 'nixtea repos update' first")
+	}
+
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return fmt.Errorf("binary %s no longer exists; run 'nixtea repos update' to rebuild", path)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to inspect binary %s: %w", path, err)
+	}
+	if info.IsDir() || info.Mode()&0111 == 0 {
+		return fmt.Errorf("binary %s is not executable", path)
+	}
+	return nil
+}
+
+// startReplica spawns one instance of a package and wires up log capture
+// and exit monitoring
+func (s *UnderSupervision) startReplica(key string, runnable *Runnable, index int, total int) error {
+	var args []string
+	if runnable.config != nil {
+		args = runnable.config.Args
+	}
+	cmd := exec.Command(runnable.BinaryPath, args...)
 	cmd.SysProcAttr = &syscall.SysProcAttr{
 		Setpgid: true,
 	}
 
-	// Create pipe for output
-	outReader, outWriter := io.Pipe()
+	// Set the environment explicitly so we can report exactly what the
+	// process was launched with; persisted entries are appended last so
+	// they win over inherited values
+	cmd.Env = os.Environ()
+	if runnable.config != nil {
+		cmd.Env = append(cmd.Env, runnable.config.Env...)
+	}
+
+	// Tag lines with the replica index only when scaled out
+	prefix := ""
+	if total > 1 {
+		prefix = fmt.Sprintf("[#%d] ", index)
+	}
 
-	// Set up command output
-	cmd.Stdout = outWriter
+	// Set up stdout/stderr pipes so output can be retained
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("stdout pipe error: %w", err)
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return fmt.Errorf("stderr pipe error: %w", err)
+	}
 
 	processState := &ProcessState{
 		Cmd:       cmd,
 		Done:      make(chan error, 1),
 		StartTime: time.Now(),
-		outReader: outReader,
-		outWriter: outWriter,
+		Replica:   index,
 	}
 
 	if err := cmd.Start(); err != nil {
-		outReader.Close()
-		outWriter.Close()
 		return fmt.Errorf("failed to start process: %w", err)
 	}
 
+	// Packages with a startup grace window stay in "starting" until
+	// they've been up that long; everything else is "running" right away
+	grace := time.Duration(0)
+	if runnable.config != nil && runnable.config.StartupGraceSeconds > 0 {
+		grace = time.Duration(runnable.config.StartupGraceSeconds) * time.Second
+	}
+
 	s.mu.Lock()
-	runnable.process = processState
-	runnable.Status = "running"
-	runnable.PID = cmd.Process.Pid
+	runnable.procs = append(runnable.procs, processState)
+	if index == 0 {
+		runnable.process = processState
+		runnable.PID = cmd.Process.Pid
+	}
+	if grace > 0 {
+		runnable.Status = "starting"
+	} else {
+		runnable.Status = "running"
+	}
+	runnable.env = cmd.Env
 	s.mu.Unlock()
 
+	s.recordEvent(runnable, Event{Type: "started", Key: key, Name: runnable.Name, PID: cmd.Process.Pid})
+
+	// Promote to "running" once the grace window passes with the process
+	// still up. An early exit wins: the monitor goroutine will have
+	// already flipped the status to stopped.
+	if index == 0 && grace > 0 {
+		time.AfterFunc(grace, func() {
+			s.mu.Lock()
+			ready := runnable.Status == "starting" && len(runnable.procs) > 0
+			pid := runnable.PID
+			if ready {
+				runnable.Status = "running"
+			}
+			s.mu.Unlock()
+
+			if ready {
+				s.recordEvent(runnable, Event{Type: "ready", Key: key, Name: runnable.Name, PID: pid})
+			}
+		})
+	}
+
+	// Scan stdout into the retained buffer
+	go func() {
+		scanner := bufio.NewScanner(stdout)
+		scanner.Buffer(make([]byte, 64*1024), logScanBufferBytes)
+		for scanner.Scan() {
+			runnable.addLogLine("stdout", prefix+sanitizeLogLine(scanner.Text()))
+		}
+		if err := scanner.Err(); err != nil {
+			runnable.addLogLine("stdout", fmt.Sprintf("%s[log capture error: %v]", prefix, err))
+		}
+	}()
+
+	// Scan stderr into the retained buffer
+	go func() {
+		scanner := bufio.NewScanner(stderr)
+		scanner.Buffer(make([]byte, 64*1024), logScanBufferBytes)
+		for scanner.Scan() {
+			runnable.addLogLine("stderr", fmt.Sprintf("%s[stderr] %s", prefix, sanitizeLogLine(scanner.Text())))
+		}
+		if err := scanner.Err(); err != nil {
+			runnable.addLogLine("stderr", fmt.Sprintf("%s[log capture error: %v]", prefix, err))
+		}
+	}()
+
 	// Monitor process in a goroutine
 	go func() {
 		err := cmd.Wait()
 
+		// The pid is gone; forget its CPU sampling baseline
+		dropCPUSample(cmd.Process.Pid)
+
 		s.mu.Lock()
-		runnable.Status = "stopped"
-		runnable.PID = 0
+		// Drop this replica from the live set
+		for i, proc := range runnable.procs {
+			if proc == processState {
+				runnable.procs = append(runnable.procs[:i], runnable.procs[i+1:]...)
+				break
+			}
+		}
+		lastDown := len(runnable.procs) == 0
+		if lastDown {
+			runnable.Status = "stopped"
+			runnable.PID = 0
+		} else if runnable.process == processState {
+			// Keep the reported PID pointing at a live replica
+			runnable.process = runnable.procs[0]
+			runnable.PID = runnable.procs[0].Cmd.Process.Pid
+		}
 		s.mu.Unlock()
 
-		outWriter.Close()
+		if err != nil {
+			s.recordEvent(runnable, Event{Type: "crashed", Key: key, Name: runnable.Name, Detail: err.Error()})
+		} else {
+			s.recordEvent(runnable, Event{Type: "stopped", Key: key, Name: runnable.Name})
+		}
+
+		if lastDown {
+			runnable.closeLogSubs()
+		}
 		processState.Done <- err
 	}()
 
 	return nil
 }
 
-// Stop stops a running package
+// Stop stops a running package, terminating every replica
 func (s *UnderSupervision) Stop(key string) error {
 	s.mu.Lock()
 	runnable, exists := s.items[key]
+	var procs []*ProcessState
+	if exists {
+		procs = make([]*ProcessState, len(runnable.procs))
+		copy(procs, runnable.procs)
+	}
 	s.mu.Unlock()
 
 	if !exists {
 		return fmt.Errorf("package %s not found", key)
 	}
 
-	if runnable.Status != "running" || runnable.process == nil {
-		return fmt.Errorf("package %s is not running", key)
+	if runnable.Status == "build_failed" {
+		return fmt.Errorf("package %s failed to build: %v (run 'nixtea repos update' to retry)", key, runnable.buildError)
 	}
 
-	// Get the process group ID
-	pgid, err := syscall.Getpgid(runnable.PID)
-	if err != nil {
-		return fmt.Errorf("failed to get process group: %w", err)
+	if (runnable.Status != "running" && runnable.Status != "starting") || len(procs) == 0 {
+		return fmt.Errorf("package %s is not running", key)
 	}
 
-	// Send SIGTERM to the process group
-	if err := syscall.Kill(-pgid, syscall.SIGTERM); err != nil {
-		return fmt.Errorf("failed to terminate process: %w", err)
-	}
+	for _, proc := range procs {
+		pid := proc.Cmd.Process.Pid
 
-	// Wait for process to exit with timeout
-	select {
-	case err := <-runnable.process.Done:
-		if err != nil && err.Error() != "signal: terminated" {
-			return fmt.Errorf("process terminated with error: %w", err)
+		// Get the process group ID
+		pgid, err := syscall.Getpgid(pid)
+		if err != nil {
+			return fmt.Errorf("failed to get process group: %w", err)
+		}
+
+		// Send SIGTERM to the process group
+		if err := syscall.Kill(-pgid, syscall.SIGTERM); err != nil {
+			return fmt.Errorf("failed to terminate process: %w", err)
 		}
-	case <-time.After(5 * time.Second):
-		// Force kill if timeout
-		log.Warn("Process didn't terminate gracefully, forcing kill",
-			"package", key,
-			"pid", runnable.PID)
-		if err := syscall.Kill(-pgid, syscall.SIGKILL); err != nil {
-			return fmt.Errorf("failed to force kill process: %w", err)
+
+		// Wait for process to exit with timeout
+		select {
+		case err := <-proc.Done:
+			if err != nil && err.Error() != "signal: terminated" {
+				return fmt.Errorf("process terminated with error: %w", err)
+			}
+		case <-time.After(5 * time.Second):
+			// Force kill if timeout
+			log.Warn("Process didn't terminate gracefully, forcing kill",
+				"package", key,
+				"pid", pid)
+			if err := syscall.Kill(-pgid, syscall.SIGKILL); err != nil {
+				return fmt.Errorf("failed to force kill process: %w", err)
+			}
 		}
 	}
 
@@ -321,6 +989,7 @@ func (s *UnderSupervision) Stop(key string) error {
 	runnable.Status = "stopped"
 	runnable.PID = 0
 	runnable.process = nil
+	runnable.procs = nil
 	s.mu.Unlock()
 
 	log.Info("Process stopped", "package", key)
@@ -341,25 +1010,147 @@ func (s *UnderSupervision) Status(name string) (string, error) {
 	return item.Status, nil
 }
 
-// Add StreamOutput method to supervisor
+// StreamOutput returns a reader over a package's logs. For a running
+// package it replays the retained buffer and then follows new lines; for
+// a stopped package it returns the retained buffer so crash output stays
+// inspectable.
 func (s *UnderSupervision) StreamOutput(key string) (io.Reader, error) {
 	s.mu.RLock()
 	runnable, exists := s.items[key]
+	running := exists && runnable != nil && runnable.Status == "running"
 	s.mu.RUnlock()
 
 	if !exists {
 		return nil, fmt.Errorf("package %s not found", key)
 	}
 
-	if runnable.Status != "running" || runnable.process == nil {
-		return nil, fmt.Errorf("package %s is not running", key)
+	if runnable.Status == "build_failed" {
+		return nil, fmt.Errorf("package %s failed to build: %v (run 'nixtea repos update' to retry)", key, runnable.buildError)
 	}
 
-	if runnable.process.outReader == nil {
-		return nil, fmt.Errorf("no output available for package %s", key)
+	runnable.logMu.Lock()
+	backlog := make([]string, len(runnable.logRecords))
+	for i, rec := range runnable.logRecords {
+		backlog[i] = rec.Text
+	}
+
+	if !running {
+		runnable.logMu.Unlock()
+		if len(backlog) == 0 {
+			return strings.NewReader(""), nil
+		}
+		return strings.NewReader(strings.Join(backlog, "\n") + "\n"), nil
 	}
 
-	return runnable.process.outReader, nil
+	sub := make(chan string, 100)
+	runnable.logSubs = append(runnable.logSubs, sub)
+	runnable.logMu.Unlock()
+
+	pr, pw := io.Pipe()
+	go func() {
+		defer pw.Close()
+		for _, line := range backlog {
+			if _, err := fmt.Fprintln(pw, line); err != nil {
+				return
+			}
+		}
+		for line := range sub {
+			if _, err := fmt.Fprintln(pw, line); err != nil {
+				return
+			}
+		}
+	}()
+
+	return pr, nil
+}
+
+// Log capture limits: lines longer than maxLogLineBytes are truncated
+// with a marker, and the scanner buffer is capped so a runaway line
+// can't kill log capture with a "token too long" error.
+const (
+	logScanBufferBytes = 1024 * 1024
+	maxLogLineBytes    = 64 * 1024
+)
+
+// sanitizeLogLine makes captured output safe to retain and stream:
+// over-long lines are truncated with a marker and binary data is
+// replaced entirely so it can't corrupt the client terminal
+func sanitizeLogLine(text string) string {
+	if strings.ContainsRune(text, 0) || !utf8.ValidString(text) {
+		return fmt.Sprintf("[binary output omitted (%d bytes)]", len(text))
+	}
+	if len(text) > maxLogLineBytes {
+		return text[:maxLogLineBytes] + " ...[truncated]"
+	}
+	return text
+}
+
+// secretEnvPattern matches environment variable names that likely hold
+// credentials and must not be printed verbatim
+var secretEnvPattern = regexp.MustCompile(`(?i)(secret|token|password|passwd|credential|api_?key|private)`)
+
+// GetEnv returns the environment the package's process was launched with,
+// redacting values whose keys look like secrets. Returns an error if the
+// package has never been started.
+func (s *UnderSupervision) GetEnv(key string) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	runnable, exists := s.items[key]
+	if !exists {
+		return nil, fmt.Errorf("package %s not found", key)
+	}
+
+	if len(runnable.env) == 0 {
+		return nil, fmt.Errorf("package %s has not been started yet", key)
+	}
+
+	env := make([]string, 0, len(runnable.env))
+	for _, kv := range runnable.env {
+		name, _, found := strings.Cut(kv, "=")
+		if found && secretEnvPattern.MatchString(name) {
+			env = append(env, name+"=[redacted]")
+			continue
+		}
+		env = append(env, kv)
+	}
+	sort.Strings(env)
+
+	return env, nil
+}
+
+// SetLabel updates a package's friendly display name in memory; the
+// caller is responsible for persisting it so the next config load
+// agrees
+func (s *UnderSupervision) SetLabel(key, label string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	runnable, exists := s.items[key]
+	if !exists {
+		return fmt.Errorf("package %s not found", key)
+	}
+
+	if runnable.config == nil {
+		runnable.config = &RunConfig{}
+	}
+	runnable.config.Label = label
+	return nil
+}
+
+// BuildFailures returns the stored build error text for every package
+// currently in build_failed state, keyed by package key
+func (s *UnderSupervision) BuildFailures() map[string]string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	failures := make(map[string]string)
+	for key, item := range s.items {
+		if item.Status == "build_failed" && item.buildError != nil {
+			failures[key] = item.buildError.Error()
+		}
+	}
+	return failures
 }
 
 // ItemState represents the public state of a runnable item
@@ -367,8 +1158,19 @@ type ItemState struct {
 	Name       string
 	Status     string
 	Pid        int
+	Replicas   int
 	BinaryPath string
 	StorePath  string
+	BuildError string
+	RepoURL    string
+	Label      string
+
+	// Lifecycle extras for state dumps and dashboards. Restarts is
+	// derived from the retained event history, so it's bounded by the
+	// history cap rather than exact over a long life.
+	StartedAt time.Time
+	Restarts  int
+	LastError string
 }
 
 // GetItems returns a copy of the current items map with public state
@@ -378,16 +1180,260 @@ func (s *UnderSupervision) GetSupervised() map[string]ItemState {
 
 	items := make(map[string]ItemState)
 	for key, item := range s.items {
-		items[key] = ItemState{
+		state := ItemState{
 			Name:       item.Name,
 			Status:     item.Status,
 			Pid:        item.PID,
+			Replicas:   len(item.procs),
 			BinaryPath: item.BinaryPath,
+			StorePath:  item.StorePath,
+			RepoURL:    item.RepoURL,
+		}
+		if item.config != nil {
+			state.Label = item.config.Label
+		}
+		if item.buildError != nil {
+			state.BuildError = item.buildError.Error()
+		}
+		if item.process != nil && len(item.procs) > 0 {
+			state.StartedAt = item.process.StartTime
+		}
+
+		item.histMu.Lock()
+		started := 0
+		for _, evt := range item.history {
+			if evt.Type == "started" {
+				started++
+			}
+			if evt.Type == "crashed" {
+				state.LastError = evt.Detail
+			}
 		}
+		item.histMu.Unlock()
+		if started > 1 {
+			state.Restarts = started - 1
+		}
+
+		items[key] = state
 	}
 	return items
 }
 
+// GetRunningPIDs returns key -> primary PID for every package that is
+// currently up, for external watchdogs and port tooling. Stopped and
+// build-failed entries are filtered out.
+func (s *UnderSupervision) GetRunningPIDs() map[string]int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	pids := make(map[string]int)
+	for key, item := range s.items {
+		if (item.Status == "running" || item.Status == "starting") && item.PID > 0 {
+			pids[key] = item.PID
+		}
+	}
+	return pids
+}
+
+// LastBuildReport returns the per-package results of the most recent
+// hydrate pass
+func (s *UnderSupervision) LastBuildReport() []BuildReportEntry {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	report := make([]BuildReportEntry, len(s.lastReport))
+	copy(report, s.lastReport)
+	return report
+}
+
+// CommandLine returns the exact command Run would execute for a
+// package: the built binary plus any persisted arguments. Purely for
+// transparency ('pkgs run --print-command').
+func (s *UnderSupervision) CommandLine(key string) (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	runnable, exists := s.items[key]
+	if !exists {
+		return "", fmt.Errorf("package %s not found", key)
+	}
+
+	parts := []string{runnable.BinaryPath}
+	if runnable.config != nil {
+		parts = append(parts, runnable.config.Args...)
+	}
+	return strings.Join(parts, " "), nil
+}
+
+// Wait blocks until the package's primary process exits and returns its
+// exit code (0 for a clean exit, -1 when the failure carries no code).
+// Used by foreground runs to propagate the child's status.
+func (s *UnderSupervision) Wait(key string) (int, error) {
+	s.mu.RLock()
+	runnable, exists := s.items[key]
+	var done chan error
+	if exists && runnable.process != nil {
+		done = runnable.process.Done
+	}
+	s.mu.RUnlock()
+
+	if !exists {
+		return 0, fmt.Errorf("package %s not found", key)
+	}
+	if done == nil {
+		return 0, fmt.Errorf("package %s is not running", key)
+	}
+
+	err := <-done
+	if err == nil {
+		return 0, nil
+	}
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		return exitErr.ExitCode(), nil
+	}
+	return -1, nil
+}
+
+// RetainedLogs returns a copy of the package's retained log buffer for
+// snapshotting, without subscribing to the live stream
+func (s *UnderSupervision) RetainedLogs(key string) ([]string, error) {
+	s.mu.RLock()
+	runnable, exists := s.items[key]
+	s.mu.RUnlock()
+	if !exists {
+		return nil, fmt.Errorf("package %s not found", key)
+	}
+
+	runnable.logMu.Lock()
+	defer runnable.logMu.Unlock()
+
+	lines := make([]string, len(runnable.logRecords))
+	for i, rec := range runnable.logRecords {
+		lines[i] = rec.Text
+	}
+	return lines, nil
+}
+
+// RetainedLogsSinceStart returns only the retained lines produced by
+// the current (or most recent) run, skipping output carried over from
+// prior runs
+func (s *UnderSupervision) RetainedLogsSinceStart(key string) ([]string, error) {
+	s.mu.RLock()
+	runnable, exists := s.items[key]
+	s.mu.RUnlock()
+	if !exists {
+		return nil, fmt.Errorf("package %s not found", key)
+	}
+
+	runnable.logMu.Lock()
+	defer runnable.logMu.Unlock()
+
+	start := runnable.runStart
+	if start > len(runnable.logRecords) {
+		start = len(runnable.logRecords)
+	}
+	lines := make([]string, 0, len(runnable.logRecords)-start)
+	for _, rec := range runnable.logRecords[start:] {
+		lines = append(lines, rec.Text)
+	}
+	return lines, nil
+}
+
+// RetainedRecords returns a copy of the package's retained log buffer
+// with per-line stream origin and capture time, for structured output
+func (s *UnderSupervision) RetainedRecords(key string) ([]RetainedLine, error) {
+	s.mu.RLock()
+	runnable, exists := s.items[key]
+	s.mu.RUnlock()
+	if !exists {
+		return nil, fmt.Errorf("package %s not found", key)
+	}
+
+	runnable.logMu.Lock()
+	defer runnable.logMu.Unlock()
+
+	records := make([]RetainedLine, len(runnable.logRecords))
+	copy(records, runnable.logRecords)
+	return records, nil
+}
+
+// DetectPort returns the first TCP port the package's processes are
+// listening on, found by parsing `ss -lptn` output for the replica PIDs.
+// Returns 0 when nothing is listening.
+func (s *UnderSupervision) DetectPort(key string) (int, error) {
+	s.mu.RLock()
+	runnable, exists := s.items[key]
+	if !exists {
+		s.mu.RUnlock()
+		return 0, fmt.Errorf("package %s not found", key)
+	}
+	if runnable.Status != "running" {
+		s.mu.RUnlock()
+		return 0, fmt.Errorf("package %s is not running", key)
+	}
+	pids := make(map[int]bool)
+	for _, proc := range runnable.procs {
+		if proc.Cmd != nil && proc.Cmd.Process != nil {
+			pids[proc.Cmd.Process.Pid] = true
+		}
+	}
+	s.mu.RUnlock()
+
+	out, err := exec.Command("ss", "-lptn").Output()
+	if err != nil {
+		return 0, fmt.Errorf("failed to list listening sockets: %w", err)
+	}
+
+	for _, line := range strings.Split(string(out), "\n") {
+		matched := false
+		for pid := range pids {
+			if strings.Contains(line, fmt.Sprintf("pid=%d,", pid)) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			continue
+		}
+
+		// ss -lptn columns: State Recv-Q Send-Q Local:Port Peer:Port Process
+		fields := strings.Fields(line)
+		if len(fields) < 4 {
+			continue
+		}
+		addr := fields[3]
+		idx := strings.LastIndex(addr, ":")
+		if idx < 0 {
+			continue
+		}
+		port, err := strconv.Atoi(addr[idx+1:])
+		if err != nil {
+			continue
+		}
+		return port, nil
+	}
+
+	return 0, nil
+}
+
+// EventHistory returns the retained lifecycle events for a package,
+// oldest first
+func (s *UnderSupervision) EventHistory(key string) ([]Event, error) {
+	s.mu.RLock()
+	runnable, exists := s.items[key]
+	s.mu.RUnlock()
+	if !exists {
+		return nil, fmt.Errorf("package %s not found", key)
+	}
+
+	runnable.histMu.Lock()
+	defer runnable.histMu.Unlock()
+
+	history := make([]Event, len(runnable.history))
+	copy(history, runnable.history)
+	return history, nil
+}
+
 // HasItems returns true if the supervisor has any items
 func (s *UnderSupervision) HasItems() bool {
 	s.mu.RLock()